@@ -1,53 +1,294 @@
 package fall
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
 )
 
 type App struct {
-	Env         Environment `env:"ENV" envDefault:"Development"`
-	router      *Router
-	middlewares []Middleware
+	Env                Environment `env:"ENV" envDefault:"Development"`
+	ShutdownTimeout    time.Duration
+	HookTimeout        time.Duration
+	router             *Router
+	container          *Container
+	middlewares        []Middleware
+	server             *http.Server
+	listener           net.Listener
+	tlsCertFile        string
+	tlsKeyFile         string
+	tlsClientCAFile    string
+	tlsClientAuth      tls.ClientAuthType
+	tlsReloadInterval  time.Duration
+	httpRedirectAddr   string
+	redirectServer     *http.Server
+	redirectPort       int
+	logger             *slog.Logger
+	stopOnce           sync.Once
+	stopErr            error
+	draining           atomic.Bool
+	livenessPath       string
+	readinessPath      string
+	healthCheckTimeout time.Duration
+	drainDelay         time.Duration
 }
 
+// NewApp creates an App for env, or for the ENV variable when env is empty,
+// which DefaultEnvConfig also looks up in its dotenv files, applying
+// middlewares to every request. See NewAppWithOptions to configure the server.
 func NewApp(env Environment, envConfig EnvConfiguration, middlewares ...Middleware) (*App, error) {
+	return NewAppWithOptions(env, envConfig, WithMiddleware(middlewares...))
+}
+
+// NewAppWithOptions creates an App like NewApp, configured by options.
+// The App and its Environment are stored in its container, the default one unless
+// WithContainer is given, as "app" and "environment".
+// Without options the server limits header size and read, write and idle times.
+func NewAppWithOptions(env Environment, envConfig EnvConfiguration, options ...Option) (*App, error) {
+	var err error
+	if defaultConfig, ok := envConfig.(*DefaultEnvConfig); ok && env == "" {
+		env, err = defaultConfig.environment()
+	} else if env == "" {
+		env, err = EnvironmentFromEnv()
+	} else {
+		env, err = ParseEnvironment(string(env))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	logger := newLogger(env)
 	app := App{
-		Env:         env,
-		router:      NewRouter(""),
-		middlewares: middlewares,
+		Env:                env,
+		ShutdownTimeout:    30 * time.Second,
+		HookTimeout:        15 * time.Second,
+		tlsReloadInterval:  time.Minute,
+		livenessPath:       "/livez",
+		readinessPath:      "/readyz",
+		healthCheckTimeout: 5 * time.Second,
+		router:             NewRouter(""),
+		container:          defaultContainer,
+		logger:             logger,
+		server: &http.Server{
+			ReadHeaderTimeout: 10 * time.Second,
+			ReadTimeout:       30 * time.Second,
+			WriteTimeout:      60 * time.Second,
+			IdleTimeout:       120 * time.Second,
+			MaxHeaderBytes:    http.DefaultMaxHeaderBytes,
+			ErrorLog:          slog.NewLogLogger(logger.Handler(), slog.LevelError),
+		},
+	}
+	for _, option := range options {
+		if err := option(&app); err != nil {
+			return nil, err
+		}
 	}
+	if app.httpRedirectAddr != "" && app.tlsCertFile == "" {
+		return nil, errors.New("WithHTTPRedirect requires WithTLS")
+	}
+	app.container.Store("app", &app)
+	app.container.Store("environment", env)
 	if envConfig != nil {
 		err := envConfig.Configure(env)
 		if err != nil {
 			return nil, err
 		}
 	}
+	if loader, ok := envConfig.(interface{ Load(targets ...any) error }); ok {
+		if err := loader.Load(app.container.configTargets()...); err != nil {
+			return nil, err
+		}
+	}
+
+	app.router.registry.logger = logger
+	if app.livenessPath != "" {
+		app.router.Get(app.livenessPath, app.handleLiveness)
+	}
+	if app.readinessPath != "" {
+		app.router.Get(app.readinessPath, app.handleReadiness)
+	}
+	if env.IsDevelopment() {
+		app.router.Get("/_fall/routes", app.handleRoutes)
+	}
 
 	app.SetControllers(
-		ResolveControllers(),
+		app.container.ResolveControllers(),
 	)
+	if err := app.router.Err(); err != nil {
+		return nil, err
+	}
 
 	return &app, nil
 }
 
+// SetControllers configures the controllers in order. Routes record the
+// controller that registered them, see RouteInfo.Controller.
 func (a *App) SetControllers(controllers []Controller) {
 	for _, handler := range controllers {
+		a.router.registry.controller = fmt.Sprintf("%T", handler)
 		handler.Configure(a.router)
 	}
+	a.router.registry.controller = ""
 }
 
-func (a *App) ListenAndServe(port string) {
-	slog.Info("HTTP Server started", "listenAddr", port)
-	stack := createStack(a.middlewares...)
-	http.ListenAndServe(fmt.Sprintf(":%s", port), stack(a.router))
+// ListenAndServe is a shortcut for Run on the given port that stops on SIGINT/SIGTERM.
+func (a *App) ListenAndServe(port string) error {
+	a.server.Addr = fmt.Sprintf(":%s", port)
+	return a.Run(context.Background())
+}
+
+// Run serves HTTP until ctx is cancelled or the process receives SIGINT/SIGTERM,
+// then drains in-flight requests for at most ShutdownTimeout.
+// Listener errors such as "address already in use" and routes that could not
+// be registered, see Router.Err, are returned immediately.
+func (a *App) Run(ctx context.Context) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := a.router.Err(); err != nil {
+		return err
+	}
+
+	listener := a.listener
+	if listener == nil {
+		addr := a.server.Addr
+		if addr == "" {
+			addr = ":http"
+		}
+		var err error
+		listener, err = net.Listen("tcp", addr)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := a.startComponents(ctx); err != nil {
+		listener.Close()
+		return err
+	}
+
+	middlewares := append([]Middleware{a.container.RequestScope}, a.middlewares...)
+	if a.tlsCertFile != "" {
+		tlsConfig, reloader, err := a.tlsConfig()
+		if err != nil {
+			listener.Close()
+			return errors.Join(err, a.stopComponents(ctx))
+		}
+		a.server.TLSConfig = tlsConfig
+		go reloader.watch(ctx, a.tlsReloadInterval)
+
+		if tlsConfig.ClientCAs != nil {
+			middlewares = append([]Middleware{clientCertificate}, middlewares...)
+		}
+		if a.httpRedirectAddr != "" {
+			if err := a.startHTTPRedirect(); err != nil {
+				listener.Close()
+				return errors.Join(err, a.stopComponents(ctx))
+			}
+		}
+	}
+
+	stack := createStack(append([]Middleware{a.withContext, a.probes, a.router.matchRoute}, middlewares...)...)
+	a.server.Handler = stack(a.router)
+	a.server.RegisterOnShutdown(a.closeHijackedConnections)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		a.logger.Info("HTTP Server started", "listenAddr", listener.Addr().String(), "tls", a.tlsCertFile != "")
+		if a.tlsCertFile != "" {
+			serveErr <- a.server.ServeTLS(listener, "", "")
+			return
+		}
+		serveErr <- a.server.Serve(listener)
+	}()
+
+	select {
+	case err := <-serveErr:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		if a.redirectServer != nil {
+			a.redirectServer.Close()
+		}
+		return errors.Join(err, a.stopComponents(ctx))
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), a.ShutdownTimeout)
+	defer cancel()
+	return a.Shutdown(shutdownCtx)
+}
+
+// Shutdown fails readiness, keeps serving for the drain delay so load balancers
+// notice, then stops accepting connections, closes hijacked WebSocket
+// connections, waits for in-flight requests until ctx is done and stops the
+// components of the container in reverse dependency order. Connections still
+// open when ctx is done are closed before the components are stopped.
+func (a *App) Shutdown(ctx context.Context) error {
+	a.logger.Info("HTTP Server shutting down")
+	a.draining.Store(true)
+	if a.drainDelay > 0 {
+		timer := time.NewTimer(a.drainDelay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		}
+	}
+	var errs []error
+	if a.redirectServer != nil {
+		if err := a.redirectServer.Shutdown(ctx); err != nil {
+			errs = append(errs, err, a.redirectServer.Close())
+		}
+	}
+	// Passado o prazo, fecha as conexões para que nenhum handler siga usando
+	// componentes que já foram parados
+	if err := a.server.Shutdown(ctx); err != nil {
+		errs = append(errs, err, a.server.Close())
+	}
+	return errors.Join(append(errs, a.stopComponents(ctx))...)
+}
+
+// withContext stores the App in the request context, so rendering and logging
+// follow its environment, see RequestEnvironment.
+func (a *App) withContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), appContextKey, a)))
+	})
 }
 
 func (a *App) GetRouter() *Router {
 	return a.router
 }
 
+// Container returns the container the App resolves its components from.
+func (a *App) Container() *Container {
+	return a.container
+}
+
+type connectionCloser interface {
+	CloseConnections()
+}
+
+func (a *App) closeHijackedConnections() {
+	a.container.instances.Range(func(_, instance any) bool {
+		if closer, ok := instance.(connectionCloser); ok {
+			closer.CloseConnections()
+		}
+		return true
+	})
+}
+
 func createStack(xs ...Middleware) Middleware {
 	return func(next http.Handler) http.Handler {
 		for i := len(xs) - 1; i >= 0; i-- {
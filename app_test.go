@@ -0,0 +1,196 @@
+package fall
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+type events struct {
+	mu   sync.Mutex
+	list []string
+}
+
+func (e *events) add(event string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.list = append(e.list, event)
+}
+
+func (e *events) get() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return slices.Clone(e.list)
+}
+
+type hookComponent struct {
+	name   string
+	events *events
+}
+
+func (h *hookComponent) Start(ctx context.Context) error {
+	h.events.add("start " + h.name)
+	return nil
+}
+
+func (h *hookComponent) Stop(ctx context.Context) error {
+	h.events.add("stop " + h.name)
+	return nil
+}
+
+type hookService struct {
+	hookComponent
+	Database *hookComponent `fall:"database"`
+}
+
+// runApp runs an App of c on a local port until the returned cancel is called.
+// testClient opens a connection per request: an idle connection the default
+// client dialed but never used would hold Shutdown for five seconds.
+var testClient = &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+
+func runApp(t *testing.T, c *Container, options ...Option) (app *App, url string, cancel context.CancelFunc, done <-chan error) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	app, err = NewAppWithOptions(Test, nil, append([]Option{WithContainer(c), WithListener(listener)}, options...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() { errs <- app.Run(ctx) }()
+
+	url = "http://" + listener.Addr().String()
+	for i := 0; ; i++ {
+		resp, err := testClient.Get(url + "/livez")
+		if err == nil {
+			resp.Body.Close()
+			break
+		}
+		if i == 50 {
+			cancel()
+			t.Fatal(err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return app, url, cancel, errs
+}
+
+func TestRunHookOrder(t *testing.T) {
+	recorded := &events{}
+	c := NewContainer()
+	c.Register("service", func() (any, error) {
+		return &hookService{hookComponent: hookComponent{name: "service", events: recorded}}, nil
+	})
+	c.Register("database", func() (any, error) {
+		return &hookComponent{name: "database", events: recorded}, nil
+	}, As[*hookComponent]())
+
+	_, _, cancel, done := runApp(t, c)
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"start database", "start service", "stop service", "stop database"}
+	if got := recorded.get(); !slices.Equal(got, want) {
+		t.Errorf("events = %v, want %v", got, want)
+	}
+}
+
+func TestRunStartFailure(t *testing.T) {
+	c := NewContainer()
+	c.Register("broken", func() (any, error) { return &failingStart{}, nil })
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	app, err := NewAppWithOptions(Test, nil, WithContainer(c), WithListener(listener))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := app.Run(context.Background()); !errors.Is(err, errStartFailed) {
+		t.Errorf("err = %v, want errStartFailed", err)
+	}
+}
+
+var errStartFailed = errors.New("start failed")
+
+type failingStart struct{}
+
+func (failingStart) Start(ctx context.Context) error {
+	return errStartFailed
+}
+
+func TestShutdownClosesConnectionsAfterTimeout(t *testing.T) {
+	entered, finished := make(chan struct{}), make(chan struct{})
+	app, url, cancel, done := runApp(t, NewContainer(), WithShutdownTimeout(50*time.Millisecond))
+	app.GetRouter().Get("/block", func(w http.ResponseWriter, r *http.Request) {
+		close(entered)
+		<-r.Context().Done()
+		close(finished)
+	})
+
+	go func() {
+		if resp, err := testClient.Get(url + "/block"); err == nil {
+			resp.Body.Close()
+		}
+	}()
+	<-entered
+	cancel()
+
+	if err := <-done; !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+	select {
+	case <-finished:
+	case <-time.After(time.Second):
+		t.Error("the handler is still running after Run returned")
+	}
+}
+
+func TestNewAppMiddlewares(t *testing.T) {
+	header := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Middleware", "applied")
+			next.ServeHTTP(w, r)
+		})
+	}
+	app, err := NewApp(Test, nil, header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	app.GetRouter().Get("/hello", func(w http.ResponseWriter, r *http.Request) {})
+	if app.listener, err = net.Listen("tcp", "127.0.0.1:0"); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- app.Run(ctx) }()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	var resp *http.Response
+	for i := 0; i < 50; i++ {
+		if resp, err = testClient.Get("http://" + app.listener.Addr().String() + "/hello"); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if got := resp.Header.Get("X-Middleware"); got != "applied" {
+		t.Errorf("X-Middleware = %q, want applied", got)
+	}
+}
@@ -0,0 +1,107 @@
+package fall
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+)
+
+type routesController struct {
+	configured *[]string
+	name       string
+	order      int
+	paths      []string
+}
+
+func (c *routesController) Configure(r *Router) {
+	*c.configured = append(*c.configured, c.name)
+	for _, path := range c.paths {
+		r.Get(path, noopHandler)
+	}
+}
+
+type orderedController struct {
+	routesController
+}
+
+func (c *orderedController) Order() int {
+	return c.order
+}
+
+func TestControllerOrder(t *testing.T) {
+	var configured []string
+	c := NewContainer()
+	c.Register("last", func() (any, error) {
+		return &orderedController{routesController{configured: &configured, name: "last", order: 100}}, nil
+	})
+	c.Register("users", func() (any, error) {
+		return &routesController{configured: &configured, name: "users"}, nil
+	})
+	c.Register("first", func() (any, error) {
+		return &orderedController{routesController{configured: &configured, name: "first", order: -1}}, nil
+	})
+	c.Register("posts", func() (any, error) {
+		return &routesController{configured: &configured, name: "posts"}, nil
+	})
+
+	if _, err := NewAppWithOptions(Test, nil, WithContainer(c)); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(configured, " "); got != "first users posts last" {
+		t.Errorf("configured = %s, want first users posts last", got)
+	}
+}
+
+func TestControllerRouteConflicts(t *testing.T) {
+	var configured []string
+	c := NewContainer()
+	c.Register("users", func() (any, error) {
+		return &routesController{configured: &configured, paths: []string{"/users/{id}"}}, nil
+	})
+	c.Register("admin", func() (any, error) {
+		return &orderedController{routesController{configured: &configured, order: 1, paths: []string{"/users/{id}", "/{resource}/1", "/bad/{"}}}, nil
+	})
+
+	_, err := NewAppWithOptions(Test, nil, WithContainer(c))
+	if err == nil {
+		t.Fatal("NewAppWithOptions accepted conflicting routes")
+	}
+	for _, want := range []string{
+		"duplicate route GET /users/{id} by *fall.orderedController, already registered by *fall.routesController",
+		"route GET /{resource}/1 by *fall.orderedController conflicts with GET /users/{id} by *fall.routesController",
+		"invalid route GET /bad/{ by *fall.orderedController",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not report %q", err, want)
+		}
+	}
+}
+
+func TestRunReportsLateConflicts(t *testing.T) {
+	mounted := NewRouter("")
+	mounted.Get("/users", noopHandler)
+	mounted.Get("/users", noopHandler)
+
+	tests := map[string]func(r *Router){
+		"router":  func(r *Router) { r.Get("/livez", noopHandler) },
+		"mounted": func(r *Router) { r.Mount("/admin", mounted) },
+	}
+	for name, register := range tests {
+		t.Run(name, func(t *testing.T) {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer listener.Close()
+			app, err := NewAppWithOptions(Test, nil, WithContainer(NewContainer()), WithListener(listener))
+			if err != nil {
+				t.Fatal(err)
+			}
+			register(app.GetRouter())
+			if err := app.Run(context.Background()); err == nil || !strings.Contains(err.Error(), "duplicate route") {
+				t.Errorf("Run = %v, want a duplicate route", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package fall
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const paramsContextKey contextKey = "fall.params"
+
+var alphaRegex = regexp.MustCompile(`^[A-Za-z]+$`)
+
+// paramConstraint validates a path wildcard and converts it to its typed value.
+type paramConstraint struct {
+	name       string
+	constraint string
+	convert    func(value string) (any, error)
+}
+
+func newParamConstraint(name, constraint string) (paramConstraint, error) {
+	param := paramConstraint{name: strings.TrimSuffix(name, "..."), constraint: constraint}
+	switch {
+	case constraint == "int":
+		param.convert = func(value string) (any, error) { return strconv.ParseInt(value, 10, 64) }
+	case constraint == "uint":
+		param.convert = func(value string) (any, error) { return strconv.ParseUint(value, 10, 64) }
+	case constraint == "float":
+		param.convert = func(value string) (any, error) { return strconv.ParseFloat(value, 64) }
+	case constraint == "bool":
+		param.convert = func(value string) (any, error) { return strconv.ParseBool(value) }
+	case constraint == "uuid":
+		param.convert = func(value string) (any, error) { return uuid.Parse(value) }
+	case constraint == "alpha":
+		param.convert = matchRegex(alphaRegex)
+	case strings.HasPrefix(constraint, "regex(") && strings.HasSuffix(constraint, ")"):
+		expr, err := regexp.Compile("^(?:" + constraint[len("regex("):len(constraint)-1] + ")$")
+		if err != nil {
+			return param, fmt.Errorf("invalid constraint for {%s}: %w", name, err)
+		}
+		param.convert = matchRegex(expr)
+	default:
+		return param, fmt.Errorf("unknown constraint for {%s}: %s", name, constraint)
+	}
+	return param, nil
+}
+
+func matchRegex(expr *regexp.Regexp) func(value string) (any, error) {
+	return func(value string) (any, error) {
+		if !expr.MatchString(value) {
+			return nil, fmt.Errorf("%q does not match %s", value, expr)
+		}
+		return value, nil
+	}
+}
+
+// parseConstraints turns "/users/{id:int}" into the ServeMux pattern "/users/{id}"
+// and its constraints. Braces and parentheses inside a constraint are balanced,
+// so regexes like "regex([a-z]{2,3})" are kept whole.
+func parseConstraints(path string) (string, []paramConstraint, error) {
+	var pattern strings.Builder
+	var constraints []paramConstraint
+	for i := 0; i < len(path); i++ {
+		if path[i] != '{' {
+			pattern.WriteByte(path[i])
+			continue
+		}
+		depth, end := 0, -1
+		for j := i; j < len(path) && end < 0; j++ {
+			switch path[j] {
+			case '{', '(':
+				depth++
+			case ')':
+				depth--
+			case '}':
+				depth--
+				if depth == 0 {
+					end = j
+				}
+			}
+		}
+		if end < 0 {
+			return "", nil, fmt.Errorf("unbalanced braces in %s", path)
+		}
+		wildcard := path[i+1 : end]
+		name, constraint, ok := strings.Cut(wildcard, ":")
+		pattern.WriteString("{" + name + "}")
+		if ok {
+			param, err := newParamConstraint(name, constraint)
+			if err != nil {
+				return "", nil, fmt.Errorf("%s: %w", path, err)
+			}
+			constraints = append(constraints, param)
+		}
+		i = end
+	}
+	return pattern.String(), constraints, nil
+}
+
+// validateParams answers 404 through the router fallback when a wildcard
+// does not satisfy its constraint, before any middleware of the route runs.
+func (r *Router) validateParams(constraints []paramConstraint, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		params := make(map[string]any, len(constraints))
+		for _, param := range constraints {
+			value, err := param.convert(req.PathValue(param.name))
+			if err != nil {
+				fallback := r.registry.fallbackFor(r, req.URL.Path)
+				fallback.serveFallback(w, req, fallback.notFoundHandler())
+				return
+			}
+			params[param.name] = value
+		}
+		ctx := context.WithValue(req.Context(), paramsContextKey, params)
+		next.ServeHTTP(w, req.WithContext(ctx))
+	})
+}
+
+// PathParam returns the converted value of a constrained wildcard: int64 for
+// "int", uint64 for "uint", float64 for "float", bool for "bool", uuid.UUID
+// for "uuid" and string for "alpha" and "regex(...)".
+func PathParam[T any](r *http.Request, name string) Result[T] {
+	var zero T
+	params, _ := r.Context().Value(paramsContextKey).(map[string]any)
+	value, ok := params[name]
+	if !ok {
+		return NewResult(zero, fmt.Errorf("path param %s has no constraint", name))
+	}
+	typed, ok := value.(T)
+	if !ok {
+		return NewResult(zero, fmt.Errorf("path param %s is %T, not %T", name, value, zero))
+	}
+	return NewResult(typed, nil)
+}
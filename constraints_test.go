@@ -0,0 +1,81 @@
+package fall
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseConstraints(t *testing.T) {
+	tests := []struct {
+		path    string
+		pattern string
+		params  []string
+		wantErr bool
+	}{
+		{path: "/users", pattern: "/users"},
+		{path: "/users/{id}", pattern: "/users/{id}"},
+		{path: "/users/{id:int}", pattern: "/users/{id}", params: []string{"id"}},
+		{path: "/users/{id:uuid}/posts/{slug:alpha}", pattern: "/users/{id}/posts/{slug}", params: []string{"id", "slug"}},
+		{path: "/langs/{code:regex([a-z]{2,3})}", pattern: "/langs/{code}", params: []string{"code"}},
+		{path: "/files/{path...:regex(.+)}", pattern: "/files/{path...}", params: []string{"path"}},
+		{path: "/{$}", pattern: "/{$}"},
+		{path: "/users/{id:number}", wantErr: true},
+		{path: "/users/{id:regex([a-z)}", wantErr: true},
+		{path: "/users/{id", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			pattern, constraints, err := parseConstraints(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if pattern != tt.pattern {
+				t.Errorf("pattern = %q, want %q", pattern, tt.pattern)
+			}
+			var params []string
+			for _, constraint := range constraints {
+				params = append(params, constraint.name)
+			}
+			if !slices.Equal(params, tt.params) {
+				t.Errorf("params = %v, want %v", params, tt.params)
+			}
+		})
+	}
+}
+
+func TestParamConstraintConvert(t *testing.T) {
+	tests := []struct {
+		constraint string
+		value      string
+		want       any
+		wantErr    bool
+	}{
+		{constraint: "int", value: "42", want: int64(42)},
+		{constraint: "int", value: "x", wantErr: true},
+		{constraint: "uint", value: "-1", wantErr: true},
+		{constraint: "float", value: "1.5", want: 1.5},
+		{constraint: "bool", value: "true", want: true},
+		{constraint: "alpha", value: "abc", want: "abc"},
+		{constraint: "alpha", value: "abc1", wantErr: true},
+		{constraint: "regex([a-z]{2})", value: "pt", want: "pt"},
+		{constraint: "regex([a-z]{2})", value: "pt-br", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.constraint+"/"+tt.value, func(t *testing.T) {
+			param, err := newParamConstraint("p", tt.constraint)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := param.convert(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("got %v (%T), want %v (%T)", got, got, tt.want, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package fall
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestChildContainers(t *testing.T) {
+	parent := NewContainer()
+	parent.Register("repository", func() (any, error) { return &repository{name: "shared"}, nil }, As[*repository]())
+	first, second := parent.NewChild(), parent.NewChild()
+	for _, c := range []*Container{first, second} {
+		c.Register("service", func() (any, error) { return &scopedService{}, nil })
+	}
+	second.Register("repository", func() (any, error) { return &repository{name: "own"}, nil }, As[*repository]())
+
+	firstService := GetNamedFrom[*scopedService](first, "service")
+	if firstService.Error != nil {
+		t.Fatal(firstService.Error)
+	}
+	if shared := GetNamedFrom[*repository](parent, "repository"); firstService.Value.Repository != shared.Value {
+		t.Error("the repository resolved through the child is not the one of the parent")
+	}
+	if secondService := GetNamedFrom[*scopedService](second, "service"); secondService.Value.Repository.name != "own" {
+		t.Errorf("second child repository = %q, want its own", secondService.Value.Repository.name)
+	}
+	if _, err := parent.Resolve("service"); err == nil {
+		t.Error("the parent resolved a component registered in a child")
+	}
+}
+
+type homeController struct {
+	body string
+}
+
+func (c *homeController) Configure(r *Router) {
+	r.Get("/{$}", writeBody(c.body))
+}
+
+func TestAppsWithSeparateContainers(t *testing.T) {
+	var apps []*App
+	for _, body := range []string{"first", "second"} {
+		c := NewContainer()
+		c.Register("homeController", func() (any, error) { return &homeController{body: body}, nil })
+		app, err := NewAppWithOptions(Test, nil, WithContainer(c))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stored := GetNamedFrom[*App](c, "app"); stored.Value != app {
+			t.Errorf("app stored in the container = %p, want %p", stored.Value, app)
+		}
+		apps = append(apps, app)
+	}
+
+	for i, want := range []string{"first", "second"} {
+		w := httptest.NewRecorder()
+		apps[i].GetRouter().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		if w.Body.String() != want {
+			t.Errorf("app %d = %q, want %q", i, w.Body.String(), want)
+		}
+	}
+}
+
+type databaseConfig struct {
+	Host string `env:"FALL_TEST_DATABASE_HOST"`
+}
+
+type cacheConfig struct {
+	Size int `env:"FALL_TEST_CACHE_SIZE"`
+}
+
+type unrelatedConfig struct {
+	Key string `env:"FALL_TEST_UNRELATED_KEY" required:"true"`
+}
+
+func TestContainerConfigs(t *testing.T) {
+	t.Setenv("FALL_TEST_DATABASE_HOST", "db")
+	t.Setenv("FALL_TEST_CACHE_SIZE", "64")
+
+	parent := NewContainer()
+	database := &databaseConfig{}
+	parent.RegisterConfig("databaseConfig", database)
+	child := parent.NewChild()
+	cache := &cacheConfig{}
+	child.RegisterConfig("cacheConfig", cache)
+	NewContainer().RegisterConfig("unrelatedConfig", &unrelatedConfig{})
+
+	envConfig := &DefaultEnvConfig{ProfileDir: t.TempDir(), EnvFiles: []string{}}
+	if _, err := NewAppWithOptions(Test, envConfig, WithContainer(child)); err != nil {
+		t.Fatal(err)
+	}
+	if database.Host != "db" || cache.Size != 64 {
+		t.Errorf("configs = %+v %+v, want them filled", database, cache)
+	}
+}
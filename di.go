@@ -1,68 +1,484 @@
 package fall
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
 	"reflect"
+	"slices"
+	"strings"
 	"sync"
 )
 
-var (
-	constructors = make(map[string]func() (any, error))
-	instances    = sync.Map{}
-	mu           sync.Mutex
+// Container holds the components of an application. A child container falls
+// back to its parent for the components it does not register itself.
+type Container struct {
+	parent            *Container
+	constructors      map[string]func() (any, error)
+	registrationOrder []string
+	types             map[string]reflect.Type
+	lifetimes         map[string]Lifetime
+	bindings          map[reflect.Type]reflect.Type
+	groups            map[string][]string
+	dependents        map[string][]dependent
+	configs           []any
+	instances         sync.Map
+	order             []string
+	mu                sync.Mutex
+}
+
+// defaultContainer backs the package functions such as Register and Resolve.
+var defaultContainer = NewContainer()
+
+func NewContainer() *Container {
+	return &Container{
+		constructors: make(map[string]func() (any, error)),
+		types:        make(map[string]reflect.Type),
+		lifetimes:    make(map[string]Lifetime),
+		bindings:     make(map[reflect.Type]reflect.Type),
+		groups:       make(map[string][]string),
+		dependents:   make(map[string][]dependent),
+	}
+}
+
+// DefaultContainer returns the container used by the package functions.
+func DefaultContainer() *Container {
+	return defaultContainer
+}
+
+// NewChild creates a container that resolves the components it does not
+// register from c. Components resolved from c stay in c.
+func (c *Container) NewChild() *Container {
+	child := NewContainer()
+	child.parent = c
+	return child
+}
+
+func Register(name string, constructor func() (any, error), opts ...RegisterOption) {
+	defaultContainer.Register(name, constructor, opts...)
+}
+
+func (c *Container) Register(name string, constructor func() (any, error), opts ...RegisterOption) {
+	reg := registration{name: name}
+	for _, opt := range opts {
+		opt(&reg)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.addConstructor(reg.name, constructor)
+	c.lifetimes[reg.name] = reg.lifetime
+	if reg.typ != nil {
+		c.types[reg.name] = reg.typ
+	}
+	c.addToGroups(reg)
+}
+
+// addConstructor keeps the registration order of names for ResolveControllers.
+func (c *Container) addConstructor(name string, constructor func() (any, error)) {
+	if _, ok := c.constructors[name]; !ok {
+		c.registrationOrder = append(c.registrationOrder, name)
+	}
+	c.constructors[name] = constructor
+}
+
+func (c *Container) addToGroups(reg registration) {
+	for _, group := range reg.groups {
+		if !slices.Contains(c.groups[group], reg.name) {
+			c.groups[group] = append(c.groups[group], reg.name)
+		}
+	}
+}
+
+// Lifetime tells how long a resolved component is reused.
+type Lifetime int
+
+const (
+	// Singleton components are constructed once and shared by everyone.
+	Singleton Lifetime = iota
+	// Transient components are constructed every time they are resolved or injected.
+	Transient
+	// RequestScoped components are constructed once per request scope, see RequestScope.
+	RequestScoped
 )
 
-func Register(name string, constructor func() (any, error)) {
-	mu.Lock()
-	defer mu.Unlock()
-	constructors[name] = constructor
+func (l Lifetime) String() string {
+	switch l {
+	case Transient:
+		return "transient"
+	case RequestScoped:
+		return "request scoped"
+	default:
+		return "singleton"
+	}
+}
+
+type registration struct {
+	name     string
+	lifetime Lifetime
+	typ      reflect.Type
+	groups   []string
+}
+
+type RegisterOption func(r *registration)
+
+// Named registers the component under name, to tell apart several components of the same type.
+func Named(name string) RegisterOption {
+	return func(r *registration) {
+		r.name = name
+	}
+}
+
+// As declares the type returned by a constructor given to Register, so the
+// component can be found by type and validated before it is resolved.
+func As[T any]() RegisterOption {
+	return func(r *registration) {
+		r.typ = reflect.TypeFor[T]()
+	}
+}
+
+// InGroup adds the component to group, to be injected with every other
+// member into a field tagged `fall:"group:<group>"`.
+func InGroup(group string) RegisterOption {
+	return func(r *registration) {
+		r.groups = append(r.groups, group)
+	}
+}
+
+// WithLifetime registers the component with lifetime instead of Singleton.
+func WithLifetime(lifetime Lifetime) RegisterOption {
+	return func(r *registration) {
+		r.lifetime = lifetime
+	}
+}
+
+// Provide registers constructor for the type T. The component is named after
+// the type, e.g. "*services.UserService", unless Named is given, and can be
+// resolved with Get or injected into fields tagged `fall:""`.
+func Provide[T any](constructor func() (T, error), opts ...RegisterOption) {
+	ProvideIn(defaultContainer, constructor, opts...)
+}
+
+// ProvideIn is Provide for the container c.
+func ProvideIn[T any](c *Container, constructor func() (T, error), opts ...RegisterOption) {
+	typ := reflect.TypeFor[T]()
+	reg := registration{name: typ.String()}
+	for _, opt := range opts {
+		opt(&reg)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.addConstructor(reg.name, func() (any, error) { return constructor() })
+	c.types[reg.name] = typ
+	c.lifetimes[reg.name] = reg.lifetime
+	c.addToGroups(reg)
+}
+
+// Bind makes Impl the component resolved for the interface I. It panics when
+// Impl does not implement I.
+func Bind[I, Impl any]() {
+	BindIn[I, Impl](defaultContainer)
+}
+
+// BindIn is Bind for the container c.
+func BindIn[I, Impl any](c *Container) {
+	iface, impl := reflect.TypeFor[I](), reflect.TypeFor[Impl]()
+	if iface.Kind() != reflect.Interface {
+		panic(fmt.Errorf("cannot bind %s: not an interface", iface))
+	}
+	if !impl.Implements(iface) {
+		panic(fmt.Errorf("cannot bind %s to %s: %s does not implement it", iface, impl, impl))
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.bindings[iface] = impl
+}
+
+// Get resolves the component of type T: the one bound to T with Bind, or else
+// the only component whose type is T or, for interfaces, implements T.
+func Get[T any]() Result[T] {
+	return GetFrom[T](defaultContainer)
+}
+
+// GetFrom is Get for the container c.
+func GetFrom[T any](c *Container) Result[T] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	instance, err := c.resolveType(reflect.TypeFor[T](), nil, nil)
+	return typedResult[T](instance, err)
+}
+
+// GetNamed resolves the component registered under name as a T.
+func GetNamed[T any](name string) Result[T] {
+	return GetNamedFrom[T](defaultContainer, name)
+}
+
+// GetNamedFrom is GetNamed for the container c.
+func GetNamedFrom[T any](c *Container, name string) Result[T] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	instance, err := c.resolve(name, nil, nil)
+	return namedResult[T](name, instance, err)
+}
+
+func namedResult[T any](name string, instance any, err error) Result[T] {
+	if _, ok := instance.(T); err == nil && !ok {
+		err = fmt.Errorf("dependency %s is %T, not %s", name, instance, reflect.TypeFor[T]())
+	}
+	return typedResult[T](instance, err)
+}
+
+func typedResult[T any](instance any, err error) Result[T] {
+	if err != nil {
+		var zero T
+		return NewResult(zero, err)
+	}
+	value, _ := instance.(T)
+	return NewResult(value, nil)
 }
 
 func Resolve(name string) (any, error) {
-	mu.Lock()
-	defer mu.Unlock()
-	return resolve(name)
+	return defaultContainer.Resolve(name)
 }
 
-func resolve(name string) (any, error) {
-	if instance, ok := instances.Load(name); ok {
-		return instance, nil
+func (c *Container) Resolve(name string) (any, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.resolve(name, nil, nil)
+}
+
+// resolve returns the component registered under name. Request-scoped
+// components and their dependencies are resolved within scope, which is nil
+// outside of a request; singletons never depend on a scope. path holds the
+// names being resolved that led to name. Outside of a scope c.mu must be
+// held; within one, the scope is locked instead and c.mu is only taken to
+// read registrations and to build singletons, so requests do not wait on
+// each other.
+func (c *Container) resolve(name string, scope *Scope, path []string) (any, error) {
+	if slices.Contains(path, name) {
+		return nil, &ResolutionError{Path: append(slices.Clone(path), name), Err: ErrDependencyCycle}
+	}
+	unlock := c.lockFor(scope)
+	has, lifetime, construtor := c.has(name), c.lifetimes[name], c.constructors[name]
+	unlock()
+	if !has && c.parent != nil {
+		if scope == nil {
+			c.parent.mu.Lock()
+			defer c.parent.mu.Unlock()
+		}
+		return c.parent.resolve(name, scope, path)
+	}
+
+	switch lifetime {
+	case Singleton:
+		if instance, ok := c.instances.Load(name); ok {
+			return instance, nil
+		}
+		if scope != nil {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			return c.resolve(name, nil, path)
+		}
+	case RequestScoped:
+		if scope == nil {
+			return nil, resolutionError(append(slices.Clone(path), name), errors.New("request scoped: resolve it within a request scope or inject it as fall.Scoped"))
+		}
+		if instance, ok := scope.get(name); ok {
+			return instance, nil
+		}
 	}
+	path = append(slices.Clone(path), name)
 
-	construtor, ok := constructors[name]
-	if !ok {
-		return nil, fmt.Errorf("dependency not registered: %s", name)
+	if construtor == nil {
+		return nil, resolutionError(path, ErrDependencyNotRegistered)
 	}
 
 	instance, err := construtor()
 	if err != nil {
-		return nil, fmt.Errorf("construction failed for %s: %w", name, err)
+		return nil, resolutionError(path, fmt.Errorf("construction failed: %w", err))
 	}
 
-	if err := autoInject(instance); err != nil {
-		return nil, fmt.Errorf("injection failed for %s: %w", name, err)
+	if err := c.autoInject(instance, scope, path); err != nil {
+		return nil, resolutionError(path, fmt.Errorf("injection failed: %w", err))
 	}
 
 	if initializable, ok := instance.(Initializable); ok {
 		if err := initializable.Init(); err != nil {
-			return nil, fmt.Errorf("initialization failed for %s: %w", name, err)
+			return nil, resolutionError(path, fmt.Errorf("initialization failed: %w", err))
 		}
 	}
 
-	instances.Store(name, instance)
+	switch lifetime {
+	case Singleton:
+		c.instances.Store(name, instance)
+		c.order = append(c.order, name)
+	case RequestScoped:
+		scope.add(name, instance)
+	}
 	return instance, nil
 }
 
+// lockFor locks c.mu when resolving within scope, where resolve runs without
+// it, and returns the function that unlocks it.
+func (c *Container) lockFor(scope *Scope) func() {
+	if scope == nil {
+		return func() {}
+	}
+	c.mu.Lock()
+	return c.mu.Unlock
+}
+
+// has reports whether name is registered or stored in c itself.
+func (c *Container) has(name string) bool {
+	if _, ok := c.constructors[name]; ok {
+		return true
+	}
+	_, ok := c.instances.Load(name)
+	return ok
+}
+
+// ErrDependencyNotRegistered is reported when nothing is registered under a name or for a type.
+var ErrDependencyNotRegistered = errors.New("dependency not registered")
+
+// ErrDependencyCycle is reported when a component depends on itself, directly or not.
+var ErrDependencyCycle = errors.New("dependency cycle")
+
+// ResolutionError reports the chain of components being resolved when Err
+// happened, e.g. "userController -> userService -> userRepository -> userService: dependency cycle".
+type ResolutionError struct {
+	Path []string
+	Err  error
+}
+
+func (e *ResolutionError) Error() string {
+	return fmt.Sprintf("%s: %v", strings.Join(e.Path, " -> "), e.Err)
+}
+
+func (e *ResolutionError) Unwrap() error {
+	return e.Err
+}
+
+// resolutionError wraps err with path unless it already comes from a deeper
+// resolution, whose path is the complete one.
+func resolutionError(path []string, err error) error {
+	var resolutionErr *ResolutionError
+	if errors.As(err, &resolutionErr) {
+		return resolutionErr
+	}
+	return &ResolutionError{Path: path, Err: err}
+}
+
+// resolveType resolves the component for typ. Components registered with
+// Register are only known by type when registered with As.
+func (c *Container) resolveType(typ reflect.Type, scope *Scope, path []string) (any, error) {
+	unlock := c.lockFor(scope)
+	name, err := c.nameFor(typ)
+	unlock()
+	if err != nil {
+		return nil, err
+	}
+	return c.resolve(name, scope, path)
+}
+
+// nameFor returns the name of the component for typ in c or, if there is
+// none, in its parent.
+func (c *Container) nameFor(typ reflect.Type) (string, error) {
+	if impl, ok := c.bindings[typ]; ok {
+		typ = impl
+	}
+	names := c.namesOf(typ)
+	switch len(names) {
+	case 0:
+		if c.parent != nil {
+			c.parent.mu.Lock()
+			defer c.parent.mu.Unlock()
+			return c.parent.nameFor(typ)
+		}
+		return "", fmt.Errorf("%w: %s; register it with fall.Provide, or with fall.Register and fall.As", ErrDependencyNotRegistered, typ)
+	case 1:
+		return names[0], nil
+	default:
+		return "", fmt.Errorf("ambiguous dependency %s: %s; qualify it by name", typ, strings.Join(names, ", "))
+	}
+}
+
+// registered returns the type, which may be unknown, and the lifetime of
+// name in c or its parents.
+func (c *Container) registered(name string) (reflect.Type, Lifetime, bool) {
+	if c.has(name) {
+		return c.types[name], c.lifetimes[name], true
+	}
+	if c.parent == nil {
+		return nil, Singleton, false
+	}
+	c.parent.mu.Lock()
+	defer c.parent.mu.Unlock()
+	return c.parent.registered(name)
+}
+
+// namesOf returns the names registered with exactly typ or, if there are none,
+// with a type assignable to typ.
+func (c *Container) namesOf(typ reflect.Type) []string {
+	var exact, assignable []string
+	for name, known := range c.types {
+		if known == typ {
+			exact = append(exact, name)
+		} else if known.AssignableTo(typ) {
+			assignable = append(assignable, name)
+		}
+	}
+	if len(exact) == 0 {
+		exact = assignable
+	}
+	slices.Sort(exact)
+	return exact
+}
+
 func Store(name string, instance any) {
-	instances.Store(name, instance)
+	defaultContainer.Store(name, instance)
+}
+
+func (c *Container) Store(name string, instance any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, loaded := c.instances.Swap(name, instance); !loaded {
+		c.order = append(c.order, name)
+	}
+	if instance != nil {
+		c.types[name] = reflect.TypeOf(instance)
+	}
+}
+
+// ResolvedNames returns the names of the instances in the container in the
+// order they finished construction, so every name comes after its dependencies.
+func ResolvedNames() []string {
+	return defaultContainer.ResolvedNames()
+}
+
+func (c *Container) ResolvedNames() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.order)
+}
+
+func (c *Container) instance(name string) (any, bool) {
+	return c.instances.Load(name)
 }
 
 func ResolveControllers() []Controller {
+	return defaultContainer.ResolveControllers()
+}
+
+// ResolveControllers resolves every singleton and returns the controllers in
+// registration order, sorted by Order for those implementing Ordered.
+func (c *Container) ResolveControllers() []Controller {
 	var controllers []Controller
-	mu.Lock()
-	defer mu.Unlock()
-	for name := range constructors {
-		instance, err := resolve(name)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, name := range c.registrationOrder {
+		if c.lifetimes[name] != Singleton {
+			continue
+		}
+		instance, err := c.resolve(name, nil, nil)
 		if err != nil {
 			PanicIfError(err)
 		}
@@ -70,20 +486,30 @@ func ResolveControllers() []Controller {
 			controllers = append(controllers, controller)
 		}
 	}
+	slices.SortStableFunc(controllers, func(a, b Controller) int {
+		return cmp.Compare(controllerOrder(a), controllerOrder(b))
+	})
 	return controllers
 }
 
-func autoInject(instance interface{}) error {
+func controllerOrder(controller Controller) int {
+	if ordered, ok := controller.(Ordered); ok {
+		return ordered.Order()
+	}
+	return 0
+}
+
+func (c *Container) autoInject(instance interface{}, scope *Scope, path []string) error {
 	val := reflect.ValueOf(instance)
 	if val.Kind() != reflect.Ptr || val.Elem().Kind() != reflect.Struct {
 		return fmt.Errorf("target must be a pointer to struct")
 	}
 
 	elem := val.Elem()
-	return injectRecursive(elem)
+	return c.injectRecursive(elem, scope, path)
 }
 
-func injectRecursive(val reflect.Value) error {
+func (c *Container) injectRecursive(val reflect.Value, scope *Scope, path []string) error {
 	typ := val.Type()
 
 	for i := 0; i < typ.NumField(); i++ {
@@ -92,25 +518,55 @@ func injectRecursive(val reflect.Value) error {
 
 		// Processa campos embedded recursivamente
 		if field.Anonymous && fieldVal.Kind() == reflect.Struct {
-			if err := injectRecursive(fieldVal.Addr().Elem()); err != nil {
+			if err := c.injectRecursive(fieldVal.Addr().Elem(), scope, path); err != nil {
 				return err
 			}
 			continue
 		}
 
-		// Processa tags DI
-		tag := field.Tag.Get("fall")
-		if tag == "" {
+		// Processa tags DI; sem nome, a dependência é resolvida pelo tipo do campo
+		tagValue, ok := field.Tag.Lookup("fall")
+		if !ok {
 			continue
 		}
+		tag := parseInjectTag(tagValue)
 
-		dependency, err := resolve(tag)
+		if !fieldVal.CanSet() {
+			return fmt.Errorf("cannot set field %s", field.Name)
+		}
+		if scoped, ok := fieldVal.Addr().Interface().(scopedField); ok {
+			scoped.inject(c, tag.name)
+			continue
+		}
+
+		if tag.group != "" {
+			group, err := c.resolveGroup(tag.group, field.Type, scope, path)
+			if err != nil {
+				return fmt.Errorf("failed to resolve group %s for field %s: %w", tag.group, field.Name, err)
+			}
+			fieldVal.Set(group)
+			continue
+		}
+
+		name, err := c.fieldDependency(tag, field.Type, scope)
+		if tag.optional && errors.Is(err, ErrDependencyNotRegistered) {
+			continue
+		}
 		if err != nil {
-			return fmt.Errorf("failed to resolve %s for field %s: %w", tag, field.Name, err)
+			return fmt.Errorf("failed to resolve %s for field %s: %w", field.Type, field.Name, err)
 		}
 
-		if !fieldVal.CanSet() {
-			return fmt.Errorf("cannot set field %s", field.Name)
+		dependency, err := c.resolve(name, scope, path)
+		if err != nil {
+			return fmt.Errorf("failed to resolve %s for field %s: %w", name, field.Name, err)
+		}
+		c.addDependent(name, path[len(path)-1], scope)
+
+		if dependency == nil {
+			continue
+		}
+		if depType := reflect.TypeOf(dependency); !depType.AssignableTo(field.Type) {
+			return fmt.Errorf("cannot assign %s (%s) to field %s of type %s", name, depType, field.Name, field.Type)
 		}
 
 		fieldVal.Set(reflect.ValueOf(dependency))
@@ -118,3 +574,124 @@ func injectRecursive(val reflect.Value) error {
 
 	return nil
 }
+
+// fieldDependency returns the name of the component injected into a field
+// tagged with tag, reporting ErrDependencyNotRegistered when it is missing.
+func (c *Container) fieldDependency(tag injectTag, typ reflect.Type, scope *Scope) (string, error) {
+	unlock := c.lockFor(scope)
+	defer unlock()
+	if tag.name == "" {
+		return c.nameFor(typ)
+	}
+	if _, _, ok := c.registered(tag.name); !ok && tag.optional {
+		return "", fmt.Errorf("%w: %s", ErrDependencyNotRegistered, tag.name)
+	}
+	return tag.name, nil
+}
+
+// injectTag is a parsed fall struct tag: a component name, empty or "auto" to
+// inject by type, or "group:<name>", followed by options such as "optional".
+type injectTag struct {
+	name     string
+	group    string
+	optional bool
+}
+
+func parseInjectTag(tag string) injectTag {
+	name, options, _ := strings.Cut(tag, ",")
+	parsed := injectTag{name: strings.TrimSpace(name)}
+	if parsed.name == "auto" {
+		parsed.name = ""
+	}
+	if group, ok := strings.CutPrefix(parsed.name, "group:"); ok {
+		parsed.group, parsed.name = group, ""
+	}
+	for _, option := range strings.Split(options, ",") {
+		if strings.TrimSpace(option) == "optional" {
+			parsed.optional = true
+		}
+	}
+	return parsed
+}
+
+// resolveGroup resolves every member of group into a slice, in registration
+// order, or into a map by name, depending on typ.
+func (c *Container) resolveGroup(group string, typ reflect.Type, scope *Scope, path []string) (reflect.Value, error) {
+	var value reflect.Value
+	switch {
+	case typ.Kind() == reflect.Slice:
+		value = reflect.MakeSlice(typ, 0, 0)
+	case typ.Kind() == reflect.Map && typ.Key().Kind() == reflect.String:
+		value = reflect.MakeMap(typ)
+	default:
+		return value, fmt.Errorf("groups are injected into slices or maps with string keys, not %s", typ)
+	}
+	unlock := c.lockFor(scope)
+	members := c.groupMembers(group)
+	unlock()
+	for _, name := range members {
+		instance, err := c.resolve(name, scope, path)
+		if err != nil {
+			return value, err
+		}
+		c.addDependent(name, path[len(path)-1], scope)
+		member := reflect.ValueOf(instance)
+		if !member.IsValid() || !member.Type().AssignableTo(typ.Elem()) {
+			return value, fmt.Errorf("cannot use %s (%T) as %s", name, instance, typ.Elem())
+		}
+		if typ.Kind() == reflect.Slice {
+			value = reflect.Append(value, member)
+		} else {
+			value.SetMapIndex(reflect.ValueOf(name).Convert(typ.Key()), member)
+		}
+	}
+	return value, nil
+}
+
+// groupMembers returns the names in group, those of the parent first. c.mu must be held.
+func (c *Container) groupMembers(group string) []string {
+	var members []string
+	if c.parent != nil {
+		c.parent.mu.Lock()
+		members = c.parent.groupMembers(group)
+		c.parent.mu.Unlock()
+	}
+	for _, name := range c.groups[group] {
+		if !slices.Contains(members, name) {
+			members = append(members, name)
+		}
+	}
+	return members
+}
+
+// dependent is a component injected with another one, possibly owned by a
+// parent container, see Override.
+type dependent struct {
+	container *Container
+	name      string
+}
+
+// addDependent records that name was injected into the component dependent
+// of c, on the container that owns name.
+func (c *Container) addDependent(name, dependent string, scope *Scope) {
+	unlock := c.lockFor(scope)
+	defer unlock()
+	c.recordDependent(name, dependentOf(c, dependent))
+}
+
+func dependentOf(c *Container, name string) dependent {
+	return dependent{container: c, name: name}
+}
+
+// recordDependent stores d on the container owning name. c.mu must be held.
+func (c *Container) recordDependent(name string, d dependent) {
+	if !c.has(name) && c.parent != nil {
+		c.parent.mu.Lock()
+		defer c.parent.mu.Unlock()
+		c.parent.recordDependent(name, d)
+		return
+	}
+	if !slices.Contains(c.dependents[name], d) {
+		c.dependents[name] = append(c.dependents[name], d)
+	}
+}
@@ -0,0 +1,242 @@
+package fall
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type cycleA struct {
+	B *cycleB `fall:"b"`
+}
+
+type cycleB struct {
+	C *cycleC `fall:"c"`
+}
+
+type cycleC struct {
+	A *cycleA `fall:"a"`
+}
+
+type repository struct {
+	name string
+}
+
+type service struct {
+	Repository *repository `fall:""`
+}
+
+type scopedService struct {
+	Repository *repository `fall:"repository"`
+}
+
+type optionalService struct {
+	Repository *repository `fall:",optional"`
+}
+
+func newCycleContainer() *Container {
+	c := NewContainer()
+	c.Register("a", func() (any, error) { return &cycleA{}, nil }, As[*cycleA]())
+	c.Register("b", func() (any, error) { return &cycleB{}, nil }, As[*cycleB]())
+	c.Register("c", func() (any, error) { return &cycleC{}, nil }, As[*cycleC]())
+	return c
+}
+
+func TestResolveCyclePath(t *testing.T) {
+	tests := []struct {
+		name string
+		path []string
+	}{
+		{name: "a", path: []string{"a", "b", "c", "a"}},
+		{name: "b", path: []string{"b", "c", "a", "b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newCycleContainer().Resolve(tt.name)
+			if !errors.Is(err, ErrDependencyCycle) {
+				t.Fatalf("err = %v, want ErrDependencyCycle", err)
+			}
+			var resolutionErr *ResolutionError
+			if !errors.As(err, &resolutionErr) {
+				t.Fatalf("err = %v, want a ResolutionError", err)
+			}
+			if !slices.Equal(resolutionErr.Path, tt.path) {
+				t.Errorf("path = %v, want %v", resolutionErr.Path, tt.path)
+			}
+		})
+	}
+}
+
+func TestResolveMissingPath(t *testing.T) {
+	c := NewContainer()
+	c.Register("service", func() (any, error) { return &scopedService{}, nil })
+	_, err := c.Resolve("service")
+	if !errors.Is(err, ErrDependencyNotRegistered) {
+		t.Fatalf("err = %v, want ErrDependencyNotRegistered", err)
+	}
+	var resolutionErr *ResolutionError
+	if !errors.As(err, &resolutionErr) || !slices.Equal(resolutionErr.Path, []string{"service", "repository"}) {
+		t.Errorf("err = %v, want path service -> repository", err)
+	}
+}
+
+type namer interface {
+	Name() string
+}
+
+func (r *repository) Name() string {
+	return r.name
+}
+
+type cachedRepository struct {
+	name string
+}
+
+func (r *cachedRepository) Name() string {
+	return r.name
+}
+
+type namerService struct {
+	Namer namer `fall:""`
+}
+
+func TestProvideAndGet(t *testing.T) {
+	c := NewContainer()
+	ProvideIn(c, func() (*repository, error) { return &repository{name: "sql"}, nil })
+	ProvideIn(c, func() (*service, error) { return &service{}, nil })
+	ProvideIn(c, func() (*namerService, error) { return &namerService{}, nil })
+
+	svc := GetFrom[*service](c)
+	if svc.Error != nil {
+		t.Fatal(svc.Error)
+	}
+	if svc.Value.Repository == nil || svc.Value.Repository.name != "sql" {
+		t.Errorf("Repository = %v, want the provided one", svc.Value.Repository)
+	}
+	if repo := GetNamedFrom[*repository](c, "*fall.repository"); repo.Value != svc.Value.Repository {
+		t.Errorf("GetNamed = %v, %v, want the injected repository", repo.Value, repo.Error)
+	}
+	if namer := GetFrom[namer](c); namer.Error != nil || namer.Value.Name() != "sql" {
+		t.Errorf("Get[namer] = %v, %v, want the only implementation", namer.Value, namer.Error)
+	}
+	if dependent := GetFrom[*namerService](c); dependent.Error != nil || dependent.Value.Namer.Name() != "sql" {
+		t.Errorf("namer field = %v, %v", dependent.Value, dependent.Error)
+	}
+}
+
+func TestGetAmbiguousAndBind(t *testing.T) {
+	c := NewContainer()
+	ProvideIn(c, func() (*repository, error) { return &repository{name: "sql"}, nil })
+	ProvideIn(c, func() (*cachedRepository, error) { return &cachedRepository{name: "cache"}, nil })
+
+	if result := GetFrom[namer](c); result.Error == nil || !strings.Contains(result.Error.Error(), "ambiguous dependency") {
+		t.Errorf("Get[namer] error = %v, want an ambiguous dependency", result.Error)
+	}
+	BindIn[namer, *cachedRepository](c)
+	if result := GetFrom[namer](c); result.Error != nil || result.Value.Name() != "cache" {
+		t.Errorf("Get[namer] = %v, %v, want the bound implementation", result.Value, result.Error)
+	}
+}
+
+func TestGetUnknownType(t *testing.T) {
+	c := NewContainer()
+	c.Register("repository", func() (any, error) { return &repository{}, nil })
+	if result := GetFrom[*repository](c); !errors.Is(result.Error, ErrDependencyNotRegistered) {
+		t.Errorf("Get of a component registered without As = %v, want ErrDependencyNotRegistered", result.Error)
+	}
+	if result := GetNamedFrom[*service](c, "repository"); result.Error == nil {
+		t.Error("GetNamed returned a component of another type")
+	}
+}
+
+func TestBindPanics(t *testing.T) {
+	tests := map[string]func(c *Container){
+		"not an interface":   func(c *Container) { BindIn[*repository, *repository](c) },
+		"not implementing I": func(c *Container) { BindIn[namer, *service](c) },
+	}
+	for name, bind := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("BindIn did not panic")
+				}
+			}()
+			bind(NewContainer())
+		})
+	}
+}
+
+type namedOptionalService struct {
+	Repository *repository `fall:"repository,optional"`
+}
+
+type namerGroups struct {
+	List   []namer          `fall:"group:namers"`
+	ByName map[string]namer `fall:"group:namers"`
+}
+
+func TestOptionalInjection(t *testing.T) {
+	for _, registered := range []bool{false, true} {
+		c := NewContainer()
+		ProvideIn(c, func() (*optionalService, error) { return &optionalService{}, nil })
+		ProvideIn(c, func() (*namedOptionalService, error) { return &namedOptionalService{}, nil })
+		if registered {
+			ProvideIn(c, func() (*repository, error) { return &repository{name: "sql"}, nil }, Named("repository"))
+		}
+
+		byType := GetFrom[*optionalService](c)
+		byName := GetFrom[*namedOptionalService](c)
+		if byType.Error != nil || byName.Error != nil {
+			t.Fatalf("registered %v: errors %v, %v", registered, byType.Error, byName.Error)
+		}
+		if (byType.Value.Repository != nil) != registered || (byName.Value.Repository != nil) != registered {
+			t.Errorf("registered %v: fields = %v, %v", registered, byType.Value.Repository, byName.Value.Repository)
+		}
+	}
+}
+
+func TestGroupInjection(t *testing.T) {
+	parent := NewContainer()
+	parent.Register("sql", func() (any, error) { return &repository{name: "sql"}, nil }, InGroup("namers"))
+	c := parent.NewChild()
+	c.Register("cache", func() (any, error) { return &cachedRepository{name: "cache"}, nil }, InGroup("namers"))
+	c.Register("disk", func() (any, error) { return &repository{name: "disk"}, nil }, InGroup("namers"))
+	ProvideIn(c, func() (*namerGroups, error) { return &namerGroups{}, nil })
+
+	groups := GetFrom[*namerGroups](c)
+	if groups.Error != nil {
+		t.Fatal(groups.Error)
+	}
+	var names []string
+	for _, member := range groups.Value.List {
+		names = append(names, member.Name())
+	}
+	if want := []string{"sql", "cache", "disk"}; !slices.Equal(names, want) {
+		t.Errorf("slice = %v, want %v", names, want)
+	}
+	if len(groups.Value.ByName) != 3 || groups.Value.ByName["cache"].Name() != "cache" {
+		t.Errorf("map = %v", groups.Value.ByName)
+	}
+}
+
+func TestGroupInjectionErrors(t *testing.T) {
+	type wrongKind struct {
+		Namers namer `fall:"group:namers"`
+	}
+	type wrongMember struct {
+		Services []*service `fall:"group:namers"`
+	}
+
+	c := NewContainer()
+	c.Register("sql", func() (any, error) { return &repository{name: "sql"}, nil }, InGroup("namers"))
+	ProvideIn(c, func() (*wrongKind, error) { return &wrongKind{}, nil })
+	ProvideIn(c, func() (*wrongMember, error) { return &wrongMember{}, nil })
+
+	if result := GetFrom[*wrongKind](c); result.Error == nil || !strings.Contains(result.Error.Error(), "slices or maps") {
+		t.Errorf("group into an interface field = %v", result.Error)
+	}
+	if result := GetFrom[*wrongMember](c); result.Error == nil || !strings.Contains(result.Error.Error(), "cannot use sql") {
+		t.Errorf("group member of another type = %v", result.Error)
+	}
+}
@@ -0,0 +1,350 @@
+package fall
+
+import (
+	"bufio"
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RegisterConfig stores a pointer to a config struct in the default container
+// under name and marks it to be filled by DefaultEnvConfig when an App using
+// the container, or one of its children, is created.
+func RegisterConfig(name string, config any) {
+	defaultContainer.RegisterConfig(name, config)
+}
+
+// RegisterConfig is RegisterConfig for the container c.
+func (c *Container) RegisterConfig(name string, config any) {
+	val := reflect.ValueOf(config)
+	if val.Kind() != reflect.Ptr || val.Elem().Kind() != reflect.Struct {
+		panic(fmt.Errorf("config %s must be a pointer to struct", name))
+	}
+	c.mu.Lock()
+	c.configs = append(c.configs, config)
+	c.mu.Unlock()
+	c.Store(name, config)
+}
+
+// configTargets returns the configs registered in c and its parents, those of
+// the parents first.
+func (c *Container) configTargets() []any {
+	var targets []any
+	if c.parent != nil {
+		targets = c.parent.configTargets()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append(targets, c.configs...)
+}
+
+// EnvDecoder lets a field type parse its own value from an environment variable.
+type EnvDecoder interface {
+	DecodeEnv(value string) error
+}
+
+type DefaultEnvConfig struct {
+	// EnvFiles are dotenv files loaded in order, defaults to ".env".
+	EnvFiles []string
+	// ProfileDir is where config.<environment>.json files are looked up, defaults to ".".
+	ProfileDir string
+	// Decoders parses values for types that do not implement EnvDecoder or encoding.TextUnmarshaler.
+	Decoders map[reflect.Type]func(value string) (any, error)
+}
+
+// Configure loads the profile file for env and the dotenv files into the process
+// environment, without overriding variables that are already set. NewApp then
+// fills every config registered with RegisterConfig in the App container.
+func (c *DefaultEnvConfig) Configure(env Environment) error {
+	profile := filepath.Join(c.ProfileDir, fmt.Sprintf("config.%s.json", strings.ToLower(string(env))))
+	if err := loadProfileFile(profile); err != nil {
+		return err
+	}
+
+	for _, file := range c.envFiles() {
+		if err := loadEnvFile(file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Load fills each target, a pointer to struct, from its env tags. Every missing
+// or invalid variable is reported in the returned error.
+func (c *DefaultEnvConfig) Load(targets ...any) error {
+	var errs []error
+	for _, target := range targets {
+		val := reflect.ValueOf(target)
+		if val.Kind() != reflect.Ptr || val.Elem().Kind() != reflect.Struct {
+			errs = append(errs, fmt.Errorf("config target must be a pointer to struct, got %T", target))
+			continue
+		}
+		errs = append(errs, c.loadStruct(val.Elem(), "")...)
+	}
+	return errors.Join(errs...)
+}
+
+func (c *DefaultEnvConfig) loadStruct(val reflect.Value, prefix string) []error {
+	var errs []error
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		fieldVal := val.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, hasName := field.Tag.Lookup("env")
+		if !hasName {
+			if field.Type.Kind() == reflect.Struct {
+				errs = append(errs, c.loadStruct(fieldVal, prefix+field.Tag.Get("envPrefix"))...)
+			} else if field.Type.Kind() == reflect.Ptr && field.Type.Elem().Kind() == reflect.Struct {
+				if fieldVal.IsNil() {
+					fieldVal.Set(reflect.New(field.Type.Elem()))
+				}
+				errs = append(errs, c.loadStruct(fieldVal.Elem(), prefix+field.Tag.Get("envPrefix"))...)
+			}
+			continue
+		}
+		name = prefix + name
+
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			value, ok = field.Tag.Lookup("envDefault")
+		}
+		if !ok {
+			if required, _ := strconv.ParseBool(field.Tag.Get("required")); required {
+				errs = append(errs, fmt.Errorf("%s: required variable is not set", name))
+			}
+			continue
+		}
+
+		separator := field.Tag.Get("envSeparator")
+		if separator == "" {
+			separator = ","
+		}
+		if err := decodeString(fieldVal, value, separator, c.Decoders); err != nil {
+			errs = append(errs, fmt.Errorf("%s: invalid value %q: %w", name, value, err))
+		}
+	}
+	return errs
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// decodeString parses value into val. Slices are split on separator and
+// decoders take precedence over EnvDecoder and encoding.TextUnmarshaler.
+func decodeString(val reflect.Value, value, separator string, decoders map[reflect.Type]func(string) (any, error)) error {
+	if decoder, ok := decoders[val.Type()]; ok {
+		decoded, err := decoder(value)
+		if err != nil {
+			return err
+		}
+		decodedVal := reflect.ValueOf(decoded)
+		if !decodedVal.IsValid() || !decodedVal.Type().AssignableTo(val.Type()) {
+			return fmt.Errorf("decoder for %s returned %T", val.Type(), decoded)
+		}
+		val.Set(decodedVal)
+		return nil
+	}
+	if val.CanAddr() {
+		switch decoder := val.Addr().Interface().(type) {
+		case EnvDecoder:
+			return decoder.DecodeEnv(value)
+		case encoding.TextUnmarshaler:
+			return decoder.UnmarshalText([]byte(value))
+		}
+	}
+	if val.Type() == durationType {
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		val.SetInt(int64(duration))
+		return nil
+	}
+
+	switch val.Kind() {
+	case reflect.Ptr:
+		elem := reflect.New(val.Type().Elem())
+		if err := decodeString(elem.Elem(), value, separator, decoders); err != nil {
+			return err
+		}
+		val.Set(elem)
+	case reflect.Slice:
+		parts := []string{}
+		if value != "" {
+			parts = strings.Split(value, separator)
+		}
+		slice := reflect.MakeSlice(val.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := decodeString(slice.Index(i), strings.TrimSpace(part), separator, decoders); err != nil {
+				return err
+			}
+		}
+		val.Set(slice)
+	case reflect.String:
+		val.SetString(value)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		val.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(value, 10, val.Type().Bits())
+		if err != nil {
+			return err
+		}
+		val.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(value, 10, val.Type().Bits())
+		if err != nil {
+			return err
+		}
+		val.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(value, val.Type().Bits())
+		if err != nil {
+			return err
+		}
+		val.SetFloat(parsed)
+	default:
+		return fmt.Errorf("unsupported type %s", val.Type())
+	}
+	return nil
+}
+
+func (c *DefaultEnvConfig) envFiles() []string {
+	if c.EnvFiles == nil {
+		return []string{".env"}
+	}
+	return c.EnvFiles
+}
+
+// environment returns the environment from the ENV variable or, when the
+// process does not set it, from the first dotenv file that does.
+func (c *DefaultEnvConfig) environment() (Environment, error) {
+	if _, ok := os.LookupEnv("ENV"); !ok {
+		for _, file := range c.envFiles() {
+			var value string
+			var found bool
+			err := parseEnvFile(file, func(key, v string) error {
+				if key == "ENV" && !found {
+					value, found = v, true
+				}
+				return nil
+			})
+			if err != nil {
+				return "", err
+			}
+			if found && value != "" {
+				return ParseEnvironment(value)
+			}
+		}
+	}
+	return EnvironmentFromEnv()
+}
+
+func setEnvIfUnset(key, value string) error {
+	if _, ok := os.LookupEnv(key); ok {
+		return nil
+	}
+	return os.Setenv(key, value)
+}
+
+func loadEnvFile(path string) error {
+	return parseEnvFile(path, setEnvIfUnset)
+}
+
+// parseEnvFile calls fn for each KEY=VALUE line of the dotenv file at path,
+// doing nothing when the file does not exist.
+func parseEnvFile(path string, fn func(key, value string) error) error {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return fmt.Errorf("%s:%d: expected KEY=VALUE", path, lineNumber)
+		}
+		value = strings.TrimSpace(value)
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		} else if len(value) > 1 && value[0] == '\'' && value[len(value)-1] == '\'' {
+			value = value[1 : len(value)-1]
+		} else if i := strings.Index(value, " #"); i >= 0 {
+			value = strings.TrimSpace(value[:i])
+		}
+		if err := fn(strings.TrimSpace(key), value); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// loadProfileFile reads a JSON object whose keys are variable names. Nested
+// objects are flattened with "_", so {"db": {"host": "x"}} sets DB_HOST.
+func loadProfileFile(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var profile map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&profile); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return setProfileValues("", profile)
+}
+
+func setProfileValues(prefix string, values map[string]any) error {
+	for key, value := range values {
+		key = prefix + strings.ToUpper(key)
+		switch v := value.(type) {
+		case map[string]any:
+			if err := setProfileValues(key+"_", v); err != nil {
+				return err
+			}
+		case []any:
+			parts := make([]string, len(v))
+			for i, part := range v {
+				parts[i] = fmt.Sprint(part)
+			}
+			if err := setEnvIfUnset(key, strings.Join(parts, ",")); err != nil {
+				return err
+			}
+		case nil:
+		default:
+			if err := setEnvIfUnset(key, fmt.Sprint(v)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
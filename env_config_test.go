@@ -0,0 +1,230 @@
+package fall
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+type level int
+
+func (l *level) DecodeEnv(value string) error {
+	switch value {
+	case "low":
+		*l = 1
+	case "high":
+		*l = 2
+	default:
+		return errors.New("unknown level")
+	}
+	return nil
+}
+
+type envTarget struct {
+	Name     string        `env:"NAME" required:"true"`
+	Port     int           `env:"PORT" envDefault:"8080"`
+	Debug    bool          `env:"DEBUG"`
+	Ratio    float64       `env:"RATIO"`
+	Timeout  time.Duration `env:"TIMEOUT" envDefault:"5s"`
+	Origins  []string      `env:"ORIGINS" envSeparator:";"`
+	Ports    []uint16      `env:"PORTS"`
+	Optional *int          `env:"OPTIONAL"`
+	Level    level         `env:"LEVEL"`
+	IP       net.IP        `env:"IP"`
+	Database struct {
+		Host string `env:"HOST"`
+	} `envPrefix:"DB_"`
+	Cache *struct {
+		Size int `env:"SIZE"`
+	} `envPrefix:"CACHE_"`
+	ignored string `env:"IGNORED"`
+}
+
+func TestDefaultEnvConfigLoad(t *testing.T) {
+	for key, value := range map[string]string{
+		"NAME":       "fall",
+		"DEBUG":      "true",
+		"RATIO":      "0.5",
+		"ORIGINS":    "a.com; b.com",
+		"PORTS":      "80,443",
+		"LEVEL":      "high",
+		"IP":         "10.0.0.1",
+		"DB_HOST":    "db",
+		"CACHE_SIZE": "64",
+		"IGNORED":    "x",
+	} {
+		t.Setenv(key, value)
+	}
+
+	var got envTarget
+	if err := (&DefaultEnvConfig{}).Load(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "fall" || got.Port != 8080 || !got.Debug || got.Ratio != 0.5 || got.Timeout != 5*time.Second {
+		t.Errorf("scalars = %+v", got)
+	}
+	if !slices.Equal(got.Origins, []string{"a.com", "b.com"}) || !slices.Equal(got.Ports, []uint16{80, 443}) {
+		t.Errorf("slices = %v %v", got.Origins, got.Ports)
+	}
+	if got.Optional != nil {
+		t.Errorf("Optional = %v, want nil", *got.Optional)
+	}
+	if got.Level != 2 || !got.IP.Equal(net.ParseIP("10.0.0.1")) {
+		t.Errorf("decoders = %v %v", got.Level, got.IP)
+	}
+	if got.Database.Host != "db" || got.Cache == nil || got.Cache.Size != 64 {
+		t.Errorf("nested = %+v %+v", got.Database, got.Cache)
+	}
+	if got.ignored != "" {
+		t.Error("unexported fields must be left alone")
+	}
+}
+
+func TestDefaultEnvConfigLoadErrors(t *testing.T) {
+	t.Setenv("PORT", "http")
+	t.Setenv("LEVEL", "medium")
+
+	var got envTarget
+	err := (&DefaultEnvConfig{}).Load(&got, got)
+	if err == nil {
+		t.Fatal("want an error")
+	}
+	for _, want := range []string{"NAME: required variable is not set", `PORT: invalid value "http"`, `LEVEL: invalid value "medium"`, "must be a pointer to struct"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("err = %v, want it to contain %q", err, want)
+		}
+	}
+}
+
+func TestDefaultEnvConfigDecoders(t *testing.T) {
+	type target struct {
+		Level level `env:"LEVEL"`
+	}
+	t.Setenv("LEVEL", "custom")
+	levelType := reflect.TypeFor[level]()
+
+	tests := []struct {
+		name    string
+		decoder func(string) (any, error)
+		want    level
+		wantErr string
+	}{
+		{name: "value", decoder: func(string) (any, error) { return level(7), nil }, want: 7},
+		{name: "nil", decoder: func(string) (any, error) { return nil, nil }, wantErr: "returned <nil>"},
+		{name: "wrong type", decoder: func(string) (any, error) { return "7", nil }, wantErr: "returned string"},
+		{name: "error", decoder: func(string) (any, error) { return nil, errors.New("boom") }, wantErr: "boom"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got target
+			config := &DefaultEnvConfig{Decoders: map[reflect.Type]func(string) (any, error){levelType: tt.decoder}}
+			err := config.Load(&got)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Level != tt.want {
+				t.Errorf("Level = %d, want %d", got.Level, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseEnvFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	content := `# comment
+PLAIN=value
+export EXPORTED=yes
+SPACED = padded value
+DOUBLE="quoted # not a comment\n"
+SINGLE='single $x'
+INLINE=value # comment
+EMPTY=
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]string{}
+	err := parseEnvFile(path, func(key, value string) error {
+		got[key] = value
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"PLAIN":    "value",
+		"EXPORTED": "yes",
+		"SPACED":   "padded value",
+		"DOUBLE":   "quoted # not a comment\n",
+		"SINGLE":   "single $x",
+		"INLINE":   "value",
+		"EMPTY":    "",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if err := parseEnvFile(filepath.Join(t.TempDir(), "missing"), nil); err != nil {
+		t.Errorf("missing files must be ignored, got %v", err)
+	}
+	if err := os.WriteFile(path, []byte("A=1\nbroken\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := parseEnvFile(path, func(string, string) error { return nil }); err == nil || !strings.Contains(err.Error(), ":2: expected KEY=VALUE") {
+		t.Errorf("err = %v, want a line error", err)
+	}
+}
+
+// unsetAfter removes keys set by the loaders when the test ends.
+func unsetAfter(t *testing.T, keys ...string) {
+	for _, key := range keys {
+		if _, ok := os.LookupEnv(key); ok {
+			t.Fatalf("%s is already set", key)
+		}
+		t.Cleanup(func() { os.Unsetenv(key) })
+	}
+}
+
+func TestConfigureFiles(t *testing.T) {
+	dir := t.TempDir()
+	unsetAfter(t, "FALL_TEST_DB_HOST", "FALL_TEST_DB_PORT", "FALL_TEST_HOSTS", "FALL_TEST_FROM_ENV")
+	t.Setenv("FALL_TEST_SET", "process")
+
+	profile := `{"fall_test": {"db": {"host": "profile", "port": 5432}, "hosts": ["a", "b"], "set": "profile"}}`
+	if err := os.WriteFile(filepath.Join(dir, "config.staging.json"), []byte(profile), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	env := "FALL_TEST_DB_HOST=dotenv\nFALL_TEST_FROM_ENV=dotenv\n"
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte(env), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	config := &DefaultEnvConfig{ProfileDir: dir, EnvFiles: []string{filepath.Join(dir, ".env")}}
+	if err := config.Configure(Staging); err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]string{
+		"FALL_TEST_DB_HOST":  "profile",
+		"FALL_TEST_DB_PORT":  "5432",
+		"FALL_TEST_HOSTS":    "a,b",
+		"FALL_TEST_SET":      "process",
+		"FALL_TEST_FROM_ENV": "dotenv",
+	} {
+		if got := os.Getenv(key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package fall
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+)
+
+const (
+	Development Environment = "Development"
+	Test        Environment = "Test"
+	Staging     Environment = "Staging"
+	Production  Environment = "Production"
+)
+
+const appContextKey contextKey = "fall.app"
+
+// ParseEnvironment accepts the constant names case-insensitively plus the
+// usual short forms "dev", "stage" and "prod". Any other name, e.g. "Homolog",
+// is kept as is and gets the Production defaults.
+func ParseEnvironment(value string) (Environment, error) {
+	value = strings.TrimSpace(value)
+	switch strings.ToLower(value) {
+	case "":
+		return "", errors.New("empty environment")
+	case "development", "dev":
+		return Development, nil
+	case "test":
+		return Test, nil
+	case "staging", "stage":
+		return Staging, nil
+	case "production", "prod":
+		return Production, nil
+	}
+	return Environment(value), nil
+}
+
+// EnvironmentFromEnv parses the ENV variable, defaulting to Development when it is unset.
+func EnvironmentFromEnv() (Environment, error) {
+	value := os.Getenv("ENV")
+	if value == "" {
+		return Development, nil
+	}
+	return ParseEnvironment(value)
+}
+
+// RequestEnvironment returns the environment of the App serving r, Development
+// outside of one.
+func RequestEnvironment(r *http.Request) Environment {
+	if app, ok := r.Context().Value(appContextKey).(*App); ok {
+		return app.Env
+	}
+	return Development
+}
+
+// loggerFrom returns the logger of the App serving ctx, the default one outside of it.
+func loggerFrom(ctx context.Context) *slog.Logger {
+	if app, ok := ctx.Value(appContextKey).(*App); ok {
+		return app.logger
+	}
+	return slog.Default()
+}
+
+func (e Environment) IsDevelopment() bool {
+	return e == Development
+}
+
+func (e Environment) IsTest() bool {
+	return e == Test
+}
+
+func (e Environment) IsStaging() bool {
+	return e == Staging
+}
+
+func (e Environment) IsProduction() bool {
+	return e == Production
+}
+
+// newLogger writes to the default handler, limited to warnings in Test without
+// changing the level of the default logger other Apps share.
+func newLogger(env Environment) *slog.Logger {
+	handler := slog.Default().Handler()
+	if env.IsTest() {
+		handler = minLevelHandler{Handler: handler, level: slog.LevelWarn}
+	}
+	return slog.New(handler)
+}
+
+type minLevelHandler struct {
+	slog.Handler
+	level slog.Level
+}
+
+func (h minLevelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level && h.Handler.Enabled(ctx, level)
+}
+
+func (h minLevelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return minLevelHandler{Handler: h.Handler.WithAttrs(attrs), level: h.level}
+}
+
+func (h minLevelHandler) WithGroup(name string) slog.Handler {
+	return minLevelHandler{Handler: h.Handler.WithGroup(name), level: h.level}
+}
@@ -0,0 +1,84 @@
+package fall
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseEnvironment(t *testing.T) {
+	tests := map[string]Environment{
+		"dev":        Development,
+		"Production": Production,
+		" stage ":    Staging,
+		"TEST":       Test,
+		"Homolog":    "Homolog",
+	}
+	for value, want := range tests {
+		got, err := ParseEnvironment(value)
+		if err != nil || got != want {
+			t.Errorf("ParseEnvironment(%q) = %q, %v, want %q", value, got, err, want)
+		}
+	}
+	if _, err := ParseEnvironment(" "); err == nil {
+		t.Error("ParseEnvironment accepted an empty environment")
+	}
+}
+
+func TestRequestEnvironment(t *testing.T) {
+	development, err := NewAppWithOptions(Development, nil, WithContainer(NewContainer()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	homolog, err := NewAppWithOptions("Homolog", nil, WithContainer(NewContainer()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	failing := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		renderError(w, r, errors.New("template not found"))
+	})
+	tests := []struct {
+		app     *App
+		env     Environment
+		details bool
+	}{
+		{app: development, env: Development, details: true},
+		{app: homolog, env: "Homolog"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.env), func(t *testing.T) {
+			var env Environment
+			tt.app.withContext(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				env = RequestEnvironment(r)
+			})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+			if env != tt.env {
+				t.Errorf("RequestEnvironment = %q, want %q", env, tt.env)
+			}
+
+			w := httptest.NewRecorder()
+			tt.app.withContext(failing).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+			if got := strings.Contains(w.Body.String(), "template not found"); got != tt.details {
+				t.Errorf("error details shown = %v, want %v", got, tt.details)
+			}
+		})
+	}
+}
+
+func TestTestLoggerKeepsDefaultLevel(t *testing.T) {
+	app, err := NewAppWithOptions(Test, nil, WithContainer(NewContainer()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if app.logger.Enabled(ctx, slog.LevelInfo) || !app.logger.Enabled(ctx, slog.LevelWarn) {
+		t.Error("Test App logger is not limited to warnings")
+	}
+	if !slog.Default().Enabled(ctx, slog.LevelInfo) {
+		t.Error("Test App changed the level of the default logger")
+	}
+}
@@ -0,0 +1,157 @@
+package fall
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+var probeMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodConnect,
+	http.MethodOptions,
+	http.MethodTrace,
+}
+
+// NotFound sets the handler for unmatched paths under the router prefix. It runs
+// through the router middleware chain.
+func (r *Router) NotFound(fn http.HandlerFunc) {
+	r.notFound = fn
+	r.registerFallback()
+}
+
+// MethodNotAllowed sets the handler for paths under the router prefix that match a
+// route but not its method. The Allow header is already set when it runs.
+func (r *Router) MethodNotAllowed(fn http.HandlerFunc) {
+	r.methodNotAllowed = fn
+	r.registerFallback()
+}
+
+func (r *Router) registerFallback() {
+	r.registry.mu.Lock()
+	defer r.registry.mu.Unlock()
+	if !slices.Contains(r.registry.fallbacks, r) {
+		r.registry.fallbacks = append(r.registry.fallbacks, r)
+	}
+}
+
+// ServeHTTP dispatches to the matching route. When the path matches routes of
+// other methods it answers OPTIONS with 204 and anything else with 405, both
+// with an Allow header; otherwise it answers 404. HEAD is served by GET routes.
+// Like ServeMux, paths that no route of the matching host group serves fall
+// through to the routes registered outside host groups.
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.target(req).dispatch(w, req)
+}
+
+// target returns the host group matching req, setting its host values on req,
+// or the root router when no host group matches or serves the path.
+func (r *Router) target(req *http.Request) *Router {
+	root := r.registry.root
+	if hostRouter, values := r.registry.matchHost(req.Host); hostRouter != nil {
+		for name, value := range values {
+			req.SetPathValue(name, value)
+		}
+		if hostRouter.matches(req) || !root.matches(req) {
+			return hostRouter
+		}
+	}
+	return root
+}
+
+// matches reports whether a route of r serves the path of req with any method.
+func (r *Router) matches(req *http.Request) bool {
+	if _, pattern := r.ServeMux.Handler(req); pattern != "" {
+		return true
+	}
+	return len(r.allowedMethods(req)) > 0
+}
+
+func (r *Router) dispatch(w http.ResponseWriter, req *http.Request) {
+	if _, pattern := r.ServeMux.Handler(req); pattern != "" {
+		r.ServeMux.ServeHTTP(w, req)
+		return
+	}
+
+	fallback := r.registry.fallbackFor(r, req.URL.Path)
+	allowed := r.allowedMethods(req)
+	if len(allowed) == 0 {
+		fallback.serveFallback(w, req, fallback.notFoundHandler())
+		return
+	}
+
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	if req.Method == http.MethodOptions {
+		fallback.serveFallback(w, req, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		return
+	}
+	fallback.serveFallback(w, req, fallback.methodNotAllowedHandler())
+}
+
+func (r *Router) allowedMethods(req *http.Request) []string {
+	var allowed []string
+	for _, method := range probeMethods {
+		probe := new(http.Request)
+		*probe = *req
+		probe.Method = method
+		if _, pattern := r.ServeMux.Handler(probe); pattern != "" {
+			allowed = append(allowed, method)
+		}
+	}
+	if len(allowed) > 0 && !slices.Contains(allowed, http.MethodOptions) {
+		allowed = append(allowed, http.MethodOptions)
+	}
+	return allowed
+}
+
+// fallbackFor returns the router sharing the mux of base with the longest prefix
+// of path that set a NotFound or MethodNotAllowed handler, or base itself.
+func (rr *routeRegistry) fallbackFor(base *Router, path string) *Router {
+	rr.mu.RLock()
+	defer rr.mu.RUnlock()
+	best := base
+	for _, router := range rr.fallbacks {
+		if router.ServeMux != base.ServeMux {
+			continue
+		}
+		prefix := router.format(router.prefix)
+		if prefix != "" && path != prefix && !strings.HasPrefix(path, strings.TrimSuffix(prefix, "/")+"/") {
+			continue
+		}
+		if len(prefix) >= len(best.format(best.prefix)) {
+			best = router
+		}
+	}
+	return best
+}
+
+func (r *Router) notFoundHandler() http.Handler {
+	if r.notFound != nil {
+		return r.notFound
+	}
+	return http.NotFoundHandler()
+}
+
+func (r *Router) methodNotAllowedHandler() http.Handler {
+	if r.methodNotAllowed != nil {
+		return r.methodNotAllowed
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	})
+}
+
+func (r *Router) serveFallback(w http.ResponseWriter, req *http.Request, handler http.Handler) {
+	if r == nil {
+		handler.ServeHTTP(w, req)
+		return
+	}
+	createStack(r.chain...)(handler).ServeHTTP(w, req)
+}
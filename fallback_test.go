@@ -0,0 +1,52 @@
+package fall
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouterMethodHandling(t *testing.T) {
+	r := NewRouter("")
+	r.Get("/items", noopHandler)
+	r.Post("/items", noopHandler)
+	r.Delete("/items/{id}", noopHandler)
+	r.Group("/admin", func(admin *Router) {
+		admin.Get("/users", noopHandler)
+		admin.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		})
+		admin.NotFound(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusGone)
+		})
+	})
+
+	tests := []struct {
+		method string
+		path   string
+		status int
+		allow  string
+	}{
+		{method: http.MethodGet, path: "/items", status: http.StatusOK},
+		{method: http.MethodHead, path: "/items", status: http.StatusOK},
+		{method: http.MethodPut, path: "/items", status: http.StatusMethodNotAllowed, allow: "GET, HEAD, POST, OPTIONS"},
+		{method: http.MethodOptions, path: "/items", status: http.StatusNoContent, allow: "GET, HEAD, POST, OPTIONS"},
+		{method: http.MethodGet, path: "/items/1", status: http.StatusMethodNotAllowed, allow: "DELETE, OPTIONS"},
+		{method: http.MethodGet, path: "/missing", status: http.StatusNotFound},
+		{method: http.MethodOptions, path: "/missing", status: http.StatusNotFound},
+		{method: http.MethodPost, path: "/admin/users", status: http.StatusTeapot, allow: "GET, HEAD, OPTIONS"},
+		{method: http.MethodGet, path: "/admin/missing", status: http.StatusGone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+			if allow := w.Header().Get("Allow"); allow != tt.allow {
+				t.Errorf("Allow = %q, want %q", allow, tt.allow)
+			}
+		})
+	}
+}
@@ -0,0 +1,288 @@
+package fall
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type dependency struct {
+	field  string
+	name   string
+	typ    reflect.Type
+	scoped bool
+	err    error
+}
+
+// dependenciesOf lists the fields of typ, a struct or pointer to struct,
+// tagged with fall and the component each one is injected with. c.mu must be held.
+func (c *Container) dependenciesOf(typ reflect.Type) []dependency {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil
+	}
+	var deps []dependency
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			deps = append(deps, c.dependenciesOf(field.Type)...)
+			continue
+		}
+		tagValue, ok := field.Tag.Lookup("fall")
+		if !ok {
+			continue
+		}
+		tag := parseInjectTag(tagValue)
+		dep := dependency{field: field.Name, name: tag.name, typ: field.Type}
+		if !field.IsExported() {
+			dep.err = fmt.Errorf("cannot set unexported field")
+			deps = append(deps, dep)
+			continue
+		}
+		if scoped, ok := reflect.New(field.Type).Interface().(scopedField); ok {
+			dep.scoped = true
+			dep.typ = scoped.valueType()
+		}
+		if tag.group != "" {
+			deps = append(deps, c.groupDependencies(field, tag.group)...)
+			continue
+		}
+		if dep.name == "" {
+			dep.name, dep.err = c.nameFor(dep.typ)
+		}
+		if tag.optional && c.missing(dep) {
+			continue
+		}
+		deps = append(deps, dep)
+	}
+	return deps
+}
+
+func (c *Container) groupDependencies(field reflect.StructField, group string) []dependency {
+	kind := field.Type.Kind()
+	if kind != reflect.Slice && (kind != reflect.Map || field.Type.Key().Kind() != reflect.String) {
+		err := fmt.Errorf("groups are injected into slices or maps with string keys, not %s", field.Type)
+		return []dependency{{field: field.Name, typ: field.Type, err: err}}
+	}
+	var deps []dependency
+	for _, name := range c.groupMembers(group) {
+		deps = append(deps, dependency{field: field.Name, name: name, typ: field.Type.Elem()})
+	}
+	return deps
+}
+
+func (c *Container) missing(dep dependency) bool {
+	if dep.err != nil {
+		return errors.Is(dep.err, ErrDependencyNotRegistered)
+	}
+	_, _, ok := c.registered(dep.name)
+	return !ok
+}
+
+// typeOf returns the declared type of name or the type of its instance, nil
+// when neither is known. c.mu must be held.
+func (c *Container) typeOf(name string) reflect.Type {
+	if typ := c.types[name]; typ != nil {
+		return typ
+	}
+	if instance, ok := c.instances.Load(name); ok && instance != nil {
+		return reflect.TypeOf(instance)
+	}
+	return nil
+}
+
+// names returns every name registered or stored in c, sorted.
+func (c *Container) names() []string {
+	names := make([]string, 0, len(c.constructors))
+	for name := range c.constructors {
+		names = append(names, name)
+	}
+	c.instances.Range(func(name, _ any) bool {
+		if _, ok := c.constructors[name.(string)]; !ok {
+			names = append(names, name.(string))
+		}
+		return true
+	})
+	slices.Sort(names)
+	return names
+}
+
+// Validate checks the dependencies of every component of c without
+// constructing anything. It reports unregistered and ambiguous dependencies,
+// fields of incompatible types, singletons holding request-scoped components
+// and dependency cycles, all together. Components registered with Register
+// are checked through their As type, or the instance they were resolved to,
+// and reported when neither is known.
+func (c *Container) Validate() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var errs []error
+	edges := make(map[string][]string)
+	for _, name := range c.names() {
+		typ := c.typeOf(name)
+		if typ == nil {
+			errs = append(errs, &ResolutionError{Path: []string{name}, Err: errors.New("type unknown: register it with fall.As to validate its dependencies")})
+			continue
+		}
+		for _, dep := range c.dependenciesOf(typ) {
+			err := dep.err
+			if err == nil {
+				err = c.checkDependency(name, dep)
+			}
+			if err != nil {
+				errs = append(errs, &ResolutionError{Path: []string{name}, Err: fmt.Errorf("field %s: %w", dep.field, err)})
+				continue
+			}
+			if !dep.scoped {
+				edges[name] = append(edges[name], dep.name)
+			}
+		}
+	}
+	return errors.Join(append(errs, cycles(edges)...)...)
+}
+
+func (c *Container) checkDependency(name string, dep dependency) error {
+	typ, lifetime, ok := c.registered(dep.name)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrDependencyNotRegistered, dep.name)
+	}
+	if typ != nil && typ.Kind() != reflect.Interface && !typ.AssignableTo(dep.typ) {
+		return fmt.Errorf("cannot assign %s (%s) to %s", dep.name, typ, dep.typ)
+	}
+	if lifetime == RequestScoped && !dep.scoped && c.lifetimes[name] == Singleton {
+		return fmt.Errorf("singleton depends on request scoped %s: inject it as fall.Scoped", dep.name)
+	}
+	return nil
+}
+
+// cycles returns a ResolutionError for every cycle of edges.
+func cycles(edges map[string][]string) []error {
+	const (
+		visiting = iota + 1
+		visited
+	)
+	var errs []error
+	var path []string
+	state := make(map[string]int)
+	var visit func(name string)
+	visit = func(name string) {
+		state[name] = visiting
+		path = append(path, name)
+		for _, next := range edges[name] {
+			switch state[next] {
+			case visiting:
+				cycle := append(slices.Clone(path[slices.Index(path, next):]), next)
+				errs = append(errs, &ResolutionError{Path: cycle, Err: ErrDependencyCycle})
+			case 0:
+				visit(next)
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+	}
+	names := make([]string, 0, len(edges))
+	for name := range edges {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if state[name] == 0 {
+			visit(name)
+		}
+	}
+	return errs
+}
+
+// DependencyGraph holds the components of a container and the fields that
+// inject them into each other. It encodes to JSON and to DOT with DOT.
+type DependencyGraph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+type GraphNode struct {
+	Name     string `json:"name"`
+	Type     string `json:"type,omitempty"`
+	Lifetime string `json:"lifetime,omitempty"`
+	// Missing nodes are injected somewhere but not registered.
+	Missing bool `json:"missing,omitempty"`
+}
+
+type GraphEdge struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Field  string `json:"field"`
+	Scoped bool   `json:"scoped,omitempty"`
+}
+
+// Graph returns the dependency graph of c without constructing anything.
+// Components of unknown type, see As, have no outgoing edges.
+func (c *Container) Graph() DependencyGraph {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	graph := DependencyGraph{Nodes: []GraphNode{}, Edges: []GraphEdge{}}
+	nodes := make(map[string]bool)
+	addNode := func(name string) {
+		if nodes[name] {
+			return
+		}
+		nodes[name] = true
+		typ, lifetime, ok := c.registered(name)
+		node := GraphNode{Name: name, Missing: !ok}
+		if ok {
+			node.Lifetime = lifetime.String()
+		}
+		if typ != nil {
+			node.Type = typ.String()
+		}
+		graph.Nodes = append(graph.Nodes, node)
+	}
+	for _, name := range c.names() {
+		addNode(name)
+		typ := c.typeOf(name)
+		if typ == nil {
+			continue
+		}
+		for _, dep := range c.dependenciesOf(typ) {
+			to := dep.name
+			if dep.err != nil && to == "" {
+				to = dep.typ.String()
+			}
+			addNode(to)
+			graph.Edges = append(graph.Edges, GraphEdge{From: name, To: to, Field: dep.field, Scoped: dep.scoped})
+		}
+	}
+	return graph
+}
+
+// DOT returns the graph in the Graphviz DOT language. Request-scoped
+// injections are dashed and missing components are red.
+func (g DependencyGraph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph dependencies {\n")
+	for _, node := range g.Nodes {
+		label := node.Name
+		if node.Type != "" && node.Type != node.Name {
+			label += "\n" + node.Type
+		}
+		attrs := "label=" + strconv.Quote(label)
+		if node.Missing {
+			attrs += ", color=red"
+		}
+		fmt.Fprintf(&b, "\t%s [%s];\n", strconv.Quote(node.Name), attrs)
+	}
+	for _, edge := range g.Edges {
+		attrs := "label=" + strconv.Quote(edge.Field)
+		if edge.Scoped {
+			attrs += ", style=dashed"
+		}
+		fmt.Fprintf(&b, "\t%s -> %s [%s];\n", strconv.Quote(edge.From), strconv.Quote(edge.To), attrs)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
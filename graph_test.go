@@ -0,0 +1,90 @@
+package fall
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		container func() *Container
+		is        error
+		contains  string
+	}{
+		{
+			name: "valid",
+			container: func() *Container {
+				c := NewContainer()
+				ProvideIn(c, func() (*repository, error) { return &repository{}, nil })
+				ProvideIn(c, func() (*service, error) { return &service{}, nil })
+				ProvideIn(c, func() (*optionalService, error) { return &optionalService{}, nil })
+				return c
+			},
+		},
+		{
+			name: "missing by type",
+			container: func() *Container {
+				c := NewContainer()
+				ProvideIn(c, func() (*service, error) { return &service{}, nil })
+				return c
+			},
+			is: ErrDependencyNotRegistered,
+		},
+		{
+			name: "missing by name",
+			container: func() *Container {
+				c := NewContainer()
+				c.Register("service", func() (any, error) { return &scopedService{}, nil }, As[*scopedService]())
+				return c
+			},
+			is: ErrDependencyNotRegistered,
+		},
+		{
+			name:      "cycle",
+			container: newCycleContainer,
+			is:        ErrDependencyCycle,
+			contains:  "a -> b -> c -> a",
+		},
+		{
+			name: "request scoped in singleton",
+			container: func() *Container {
+				c := NewContainer()
+				c.Register("repository", func() (any, error) { return &repository{}, nil }, As[*repository](), WithLifetime(RequestScoped))
+				c.Register("service", func() (any, error) { return &scopedService{}, nil }, As[*scopedService]())
+				return c
+			},
+			contains: "singleton depends on request scoped repository",
+		},
+		{
+			name: "unknown type",
+			container: func() *Container {
+				c := NewContainer()
+				c.Register("service", func() (any, error) { return &scopedService{}, nil })
+				return c
+			},
+			contains: "service: type unknown",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.container().Validate()
+			if tt.is == nil && tt.contains == "" {
+				if err != nil {
+					t.Fatalf("err = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("err = nil, want an error")
+			}
+			if tt.is != nil && !errors.Is(err, tt.is) {
+				t.Errorf("err = %v, want %v", err, tt.is)
+			}
+			if !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("err = %v, want it to contain %q", err, tt.contains)
+			}
+		})
+	}
+}
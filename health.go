@@ -0,0 +1,150 @@
+package fall
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// HealthChecker components in the container are checked by the readiness endpoint.
+type HealthChecker interface {
+	CheckHealth(ctx context.Context) error
+}
+
+type HealthCheckFunc func(ctx context.Context) error
+
+func (f HealthCheckFunc) CheckHealth(ctx context.Context) error {
+	return f(ctx)
+}
+
+type HealthCheckResult struct {
+	Status   string `json:"status"`
+	Duration string `json:"duration"`
+	Error    string `json:"error,omitempty"`
+}
+
+type HealthReport struct {
+	Status string                       `json:"status"`
+	Checks map[string]HealthCheckResult `json:"checks,omitempty"`
+}
+
+// healthCheckerFor also covers a *gorm.DB in the container by pinging its connection pool.
+func healthCheckerFor(instance any) (HealthChecker, bool) {
+	switch component := instance.(type) {
+	case HealthChecker:
+		return component, true
+	case *gorm.DB:
+		return HealthCheckFunc(func(ctx context.Context) error {
+			db, err := component.DB()
+			if err != nil {
+				return err
+			}
+			return db.PingContext(ctx)
+		}), true
+	}
+	return nil, false
+}
+
+// CheckHealth runs every health check in the container concurrently, each
+// limited to the App health check timeout.
+func (a *App) CheckHealth(ctx context.Context) HealthReport {
+	report := HealthReport{Status: "ok", Checks: map[string]HealthCheckResult{}}
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for _, name := range a.container.ResolvedNames() {
+		instance, ok := a.container.instance(name)
+		if !ok {
+			continue
+		}
+		checker, ok := healthCheckerFor(instance)
+		if !ok {
+			continue
+		}
+		wg.Add(1)
+		go func(name string, checker HealthChecker) {
+			defer wg.Done()
+			start := time.Now()
+			err := a.runHealthCheck(ctx, checker)
+			result := HealthCheckResult{Status: "ok", Duration: time.Since(start).String()}
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				result.Status = "fail"
+				result.Error = err.Error()
+				report.Status = "fail"
+			}
+			report.Checks[name] = result
+		}(name, checker)
+	}
+	wg.Wait()
+	return report
+}
+
+func (a *App) runHealthCheck(ctx context.Context, checker HealthChecker) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, a.healthCheckTimeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		done <- checker.CheckHealth(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (a *App) handleLiveness(w http.ResponseWriter, r *http.Request) {
+	writeHealthReport(w, HealthReport{Status: "ok"})
+}
+
+// probes answers the liveness and readiness endpoints before the App
+// middlewares, so authentication or rate limiting never fails a probe.
+func (a *App) probes(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			switch r.URL.Path {
+			case a.livenessPath:
+				a.handleLiveness(w, r)
+				return
+			case a.readinessPath:
+				a.handleReadiness(w, r)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleReadiness fails while the App is draining so load balancers stop
+// sending traffic before the listener closes.
+func (a *App) handleReadiness(w http.ResponseWriter, r *http.Request) {
+	if a.draining.Load() {
+		writeHealthReport(w, HealthReport{Status: "draining"})
+		return
+	}
+	writeHealthReport(w, a.CheckHealth(r.Context()))
+}
+
+func writeHealthReport(w http.ResponseWriter, report HealthReport) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if report.Status == "ok" {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
+}
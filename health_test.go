@@ -0,0 +1,81 @@
+package fall
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func requireAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func statusOf(t *testing.T, url string) int {
+	t.Helper()
+	resp, err := testClient.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestProbesSkipAppMiddlewares(t *testing.T) {
+	_, url, cancel, done := runApp(t, NewContainer(), WithMiddleware(requireAuth))
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	for path, want := range map[string]int{"/livez": 200, "/readyz": 200, "/_fall/routes": 401} {
+		if got := statusOf(t, url+path); got != want {
+			t.Errorf("GET %s = %d, want %d", path, got, want)
+		}
+	}
+}
+
+func TestReadinessChecks(t *testing.T) {
+	var failing error
+	c := NewContainer()
+	c.Store("database", HealthCheckFunc(func(ctx context.Context) error { return failing }))
+	_, url, cancel, done := runApp(t, c)
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	if got := statusOf(t, url+"/readyz"); got != http.StatusOK {
+		t.Errorf("readyz = %d, want 200", got)
+	}
+	failing = errors.New("connection refused")
+	if got := statusOf(t, url+"/readyz"); got != http.StatusServiceUnavailable {
+		t.Errorf("readyz with a failing check = %d, want 503", got)
+	}
+	if got := statusOf(t, url+"/livez"); got != http.StatusOK {
+		t.Errorf("livez with a failing check = %d, want 200", got)
+	}
+}
+
+func TestReadinessFailsWhileDraining(t *testing.T) {
+	_, url, cancel, done := runApp(t, NewContainer(), WithDrainDelay(300*time.Millisecond))
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+
+	if got := statusOf(t, url+"/readyz"); got != http.StatusServiceUnavailable {
+		t.Errorf("readyz while draining = %d, want 503", got)
+	}
+	if got := statusOf(t, url+"/livez"); got != http.StatusOK {
+		t.Errorf("livez while draining = %d, want 200", got)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("Run = %v", err)
+	}
+}
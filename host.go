@@ -0,0 +1,115 @@
+package fall
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// hostPattern matches a Host header label by label. "{name}" captures one label
+// and "*" matches one label without capturing it.
+type hostPattern struct {
+	pattern   string
+	labels    []string
+	wildcards int
+}
+
+func parseHostPattern(pattern string) (*hostPattern, error) {
+	pattern = strings.ToLower(strings.TrimSuffix(pattern, "."))
+	if pattern == "" {
+		return nil, fmt.Errorf("empty host pattern")
+	}
+	host := &hostPattern{pattern: pattern, labels: strings.Split(pattern, ".")}
+	for _, label := range host.labels {
+		switch {
+		case label == "":
+			return nil, fmt.Errorf("invalid host pattern %q: empty label", pattern)
+		case label == "*":
+			host.wildcards++
+		case strings.HasPrefix(label, "{") && strings.HasSuffix(label, "}"):
+			if len(label) == 2 {
+				return nil, fmt.Errorf("invalid host pattern %q: empty wildcard name", pattern)
+			}
+			host.wildcards++
+		case strings.ContainsAny(label, "{}*"):
+			return nil, fmt.Errorf("invalid host pattern %q: wildcards must be whole labels", pattern)
+		}
+	}
+	return host, nil
+}
+
+func (h *hostPattern) String() string {
+	if h == nil {
+		return ""
+	}
+	return h.pattern
+}
+
+func (h *hostPattern) match(host string) (map[string]string, bool) {
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+	labels := strings.Split(strings.ToLower(strings.TrimSuffix(host, ".")), ".")
+	if len(labels) != len(h.labels) {
+		return nil, false
+	}
+	values := map[string]string{}
+	for i, label := range h.labels {
+		switch {
+		case label == "*":
+			if labels[i] == "" {
+				return nil, false
+			}
+		case strings.HasPrefix(label, "{"):
+			if labels[i] == "" {
+				return nil, false
+			}
+			values[label[1:len(label)-1]] = labels[i]
+		case label != labels[i]:
+			return nil, false
+		}
+	}
+	return values, true
+}
+
+// Host creates a group that only serves requests whose Host header matches pattern,
+// e.g. "api.example.com" or "{tenant}.example.com". Captured labels are read with
+// r.PathValue like path wildcards. The group inherits the prefix and middlewares of r.
+func (r *Router) Host(pattern string, fn func(r *Router)) {
+	host, err := parseHostPattern(pattern)
+	if err != nil {
+		panic(err)
+	}
+	router := &Router{
+		prefix:   r.prefix,
+		ServeMux: http.NewServeMux(),
+		chain:    slices.Clone(r.chain),
+		registry: r.registry,
+		host:     host,
+	}
+	r.registry.mu.Lock()
+	r.registry.hosts = append(r.registry.hosts, router)
+	r.registry.mu.Unlock()
+	fn(router)
+}
+
+// matchHost returns the host group with the fewest wildcards matching host,
+// the first registered one on ties.
+func (rr *routeRegistry) matchHost(host string) (*Router, map[string]string) {
+	rr.mu.RLock()
+	defer rr.mu.RUnlock()
+	var best *Router
+	var bestValues map[string]string
+	for _, router := range rr.hosts {
+		values, ok := router.host.match(host)
+		if !ok {
+			continue
+		}
+		if best == nil || router.host.wildcards < best.host.wildcards {
+			best, bestValues = router, values
+		}
+	}
+	return best, bestValues
+}
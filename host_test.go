@@ -0,0 +1,63 @@
+package fall
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func writeBody(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}
+}
+
+func TestHostGroups(t *testing.T) {
+	r := NewRouter("")
+	r.Get("/users", writeBody("root users"))
+	r.Host("api.example.com", func(r *Router) {
+		r.Get("/users", writeBody("api users"))
+	})
+	r.Host("{tenant}.example.com", func(r *Router) {
+		r.Get("/dashboard", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("dashboard " + r.PathValue("tenant")))
+		})
+	})
+
+	tests := []struct {
+		method, host, path string
+		status             int
+		body               string
+	}{
+		{method: http.MethodGet, host: "api.example.com", path: "/users", status: 200, body: "api users"},
+		{method: http.MethodGet, host: "API.example.com:8080", path: "/users", status: 200, body: "api users"},
+		{method: http.MethodGet, host: "acme.example.com", path: "/dashboard", status: 200, body: "dashboard acme"},
+		{method: http.MethodGet, host: "acme.example.com", path: "/users", status: 200, body: "root users"},
+		{method: http.MethodGet, host: "example.org", path: "/users", status: 200, body: "root users"},
+		{method: http.MethodGet, host: "api.example.com", path: "/dashboard", status: 404},
+		{method: http.MethodGet, host: "a.b.example.com", path: "/dashboard", status: 404},
+		{method: http.MethodPost, host: "api.example.com", path: "/users", status: 405},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.host+tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Host = tt.host
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+			if tt.body != "" && w.Body.String() != tt.body {
+				t.Errorf("body = %q, want %q", w.Body.String(), tt.body)
+			}
+		})
+	}
+}
+
+func TestParseHostPatternErrors(t *testing.T) {
+	for _, pattern := range []string{"", "api..example.com", "{}.example.com", "api-{id}.example.com", "*api.example.com"} {
+		if _, err := parseHostPattern(pattern); err == nil {
+			t.Errorf("parseHostPattern(%q) accepted an invalid pattern", pattern)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package fall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// startComponents calls Start on every Startable in dependency order. When one
+// fails, the components already started are stopped again.
+func (a *App) startComponents(ctx context.Context) error {
+	var started []string
+	for _, name := range a.container.ResolvedNames() {
+		instance, ok := a.container.instance(name)
+		if !ok {
+			continue
+		}
+		startable, ok := instance.(Startable)
+		if !ok {
+			continue
+		}
+		if err := a.runHook(ctx, func(ctx context.Context) error { return startable.Start(ctx) }); err != nil {
+			err = fmt.Errorf("start failed for %s: %w", name, err)
+			return errors.Join(err, a.stopNames(ctx, started))
+		}
+		started = append(started, name)
+	}
+	return nil
+}
+
+// stopComponents stops every Stoppable or io.Closer in reverse dependency order
+// and reports all failures together. Each hook still gets HookTimeout when the
+// drain already used up ctx.
+func (a *App) stopComponents(ctx context.Context) error {
+	a.stopOnce.Do(func() {
+		a.stopErr = a.stopNames(context.WithoutCancel(ctx), a.container.ResolvedNames())
+	})
+	return a.stopErr
+}
+
+func (a *App) stopNames(ctx context.Context, names []string) error {
+	var errs []error
+	for i := len(names) - 1; i >= 0; i-- {
+		name := names[i]
+		instance, ok := a.container.instance(name)
+		if !ok {
+			continue
+		}
+		var hook func(ctx context.Context) error
+		switch component := instance.(type) {
+		case Stoppable:
+			hook = component.Stop
+		case io.Closer:
+			hook = func(context.Context) error { return component.Close() }
+		default:
+			continue
+		}
+		if err := a.runHook(ctx, hook); err != nil {
+			errs = append(errs, fmt.Errorf("stop failed for %s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// runHook gives the hook at most HookTimeout and returns when it is exceeded
+// even if the hook ignores its context.
+func (a *App) runHook(ctx context.Context, hook func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, a.HookTimeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- hook(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
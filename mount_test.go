@@ -0,0 +1,75 @@
+package fall
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestMount(t *testing.T) {
+	admin := NewRouter("")
+	admin.Get("/users", writeBody("admin users"))
+
+	r := NewRouter("")
+	r.Group("/internal", func(internal *Router) {
+		internal.Use(requireAuth)
+		internal.Mount("/admin", admin)
+		internal.Mount("/files/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("file " + r.URL.Path))
+		}))
+	})
+
+	tests := []struct {
+		path, auth string
+		status     int
+		body       string
+	}{
+		{path: "/internal/admin/users", auth: "token", status: 200, body: "admin users"},
+		{path: "/internal/files/css/app.css", auth: "token", status: 200, body: "file /css/app.css"},
+		{path: "/internal/admin/users", status: 401},
+		{path: "/internal/admin/missing", auth: "token", status: 404},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+			if tt.body != "" && w.Body.String() != tt.body {
+				t.Errorf("body = %q, want %q", w.Body.String(), tt.body)
+			}
+		})
+	}
+}
+
+func TestMountRoutes(t *testing.T) {
+	admin := NewRouter("")
+	admin.Get("/users", noopHandler)
+
+	r := NewRouter("")
+	r.Group("/internal", func(internal *Router) {
+		internal.Use(requireAuth)
+		internal.Mount("/admin", admin)
+	})
+
+	var got []string
+	for _, route := range r.Routes() {
+		got = append(got, route.Method+" "+route.Pattern)
+		if len(route.Middlewares) != 1 {
+			t.Errorf("%s middlewares = %v, want the group middleware", route.Pattern, route.Middlewares)
+		}
+	}
+	want := []string{" /internal/admin/", "GET /internal/admin/users"}
+	if !slices.Equal(got, want) {
+		t.Errorf("routes = %q, want %q", got, want)
+	}
+	if routes := r.Routes(); !routes[0].Mounted || routes[1].Mounted {
+		t.Error("only the mount point is marked as mounted")
+	}
+}
@@ -0,0 +1,190 @@
+package fall
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"log"
+	"net"
+	"time"
+)
+
+// Option configures an App and its http.Server in NewAppWithOptions.
+type Option func(a *App) error
+
+// WithMiddleware applies middlewares to every request, in order, like the
+// middlewares given to NewApp.
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(a *App) error {
+		a.middlewares = append(a.middlewares, middlewares...)
+		return nil
+	}
+}
+
+// WithAddr sets the TCP address used by Run, ":http" when empty.
+func WithAddr(addr string) Option {
+	return func(a *App) error {
+		a.server.Addr = addr
+		return nil
+	}
+}
+
+// WithListener serves on an already opened listener, e.g. a Unix socket or a
+// socket inherited from systemd, instead of listening on the App address.
+func WithListener(listener net.Listener) Option {
+	return func(a *App) error {
+		a.listener = listener
+		return nil
+	}
+}
+
+func WithReadTimeout(timeout time.Duration) Option {
+	return func(a *App) error {
+		a.server.ReadTimeout = timeout
+		return nil
+	}
+}
+
+func WithReadHeaderTimeout(timeout time.Duration) Option {
+	return func(a *App) error {
+		a.server.ReadHeaderTimeout = timeout
+		return nil
+	}
+}
+
+func WithWriteTimeout(timeout time.Duration) Option {
+	return func(a *App) error {
+		a.server.WriteTimeout = timeout
+		return nil
+	}
+}
+
+func WithIdleTimeout(timeout time.Duration) Option {
+	return func(a *App) error {
+		a.server.IdleTimeout = timeout
+		return nil
+	}
+}
+
+func WithMaxHeaderBytes(size int) Option {
+	return func(a *App) error {
+		a.server.MaxHeaderBytes = size
+		return nil
+	}
+}
+
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(a *App) error {
+		a.ShutdownTimeout = timeout
+		return nil
+	}
+}
+
+// WithDrainDelay keeps serving for delay after shutdown begins while readiness
+// answers 503, so load balancers stop routing to the App before its listeners
+// close. The delay counts against the shutdown timeout.
+func WithDrainDelay(delay time.Duration) Option {
+	return func(a *App) error {
+		a.drainDelay = delay
+		return nil
+	}
+}
+
+// WithHookTimeout limits each Start and Stop call of the container components.
+func WithHookTimeout(timeout time.Duration) Option {
+	return func(a *App) error {
+		a.HookTimeout = timeout
+		return nil
+	}
+}
+
+func WithErrorLog(logger *log.Logger) Option {
+	return func(a *App) error {
+		a.server.ErrorLog = logger
+		return nil
+	}
+}
+
+// WithBaseContext sets the context of every request accepted on listener.
+func WithBaseContext(baseContext func(listener net.Listener) context.Context) Option {
+	return func(a *App) error {
+		a.server.BaseContext = baseContext
+		return nil
+	}
+}
+
+// WithTLS serves HTTPS using the given certificate and key files, which are
+// reloaded without a restart when they change on disk.
+func WithTLS(certFile, keyFile string) Option {
+	return func(a *App) error {
+		a.tlsCertFile = certFile
+		a.tlsKeyFile = keyFile
+		return nil
+	}
+}
+
+// WithCertReloadInterval sets how often the WithTLS files are checked for changes, one minute by default.
+func WithCertReloadInterval(interval time.Duration) Option {
+	return func(a *App) error {
+		a.tlsReloadInterval = interval
+		return nil
+	}
+}
+
+// WithClientCA enables mTLS, verifying client certificates against the CAs in caFile.
+// The verified certificate is available to handlers through ClientCertificate.
+func WithClientCA(caFile string, clientAuth tls.ClientAuthType) Option {
+	return func(a *App) error {
+		a.tlsClientCAFile = caFile
+		a.tlsClientAuth = clientAuth
+		return nil
+	}
+}
+
+// WithHTTPRedirect runs a second server on addr that redirects every request to HTTPS.
+// NewAppWithOptions fails when it is given without WithTLS.
+func WithHTTPRedirect(addr string) Option {
+	return func(a *App) error {
+		a.httpRedirectAddr = addr
+		return nil
+	}
+}
+
+// WithRedirectPort sets the public HTTPS port WithHTTPRedirect sends clients
+// to, 443 by default, e.g. when a load balancer exposes the App on 8443.
+func WithRedirectPort(port int) Option {
+	return func(a *App) error {
+		a.redirectPort = port
+		return nil
+	}
+}
+
+// WithHealthPaths changes where the liveness and readiness endpoints are
+// mounted, "/livez" and "/readyz" by default. An empty path disables the endpoint.
+func WithHealthPaths(liveness, readiness string) Option {
+	return func(a *App) error {
+		a.livenessPath = liveness
+		a.readinessPath = readiness
+		return nil
+	}
+}
+
+// WithHealthCheckTimeout limits each readiness check, five seconds by default.
+func WithHealthCheckTimeout(timeout time.Duration) Option {
+	return func(a *App) error {
+		a.healthCheckTimeout = timeout
+		return nil
+	}
+}
+
+// WithContainer makes the App resolve its controllers and components from
+// container instead of the default one, e.g. to run several Apps in one process.
+func WithContainer(container *Container) Option {
+	return func(a *App) error {
+		if container == nil {
+			return errors.New("container must not be nil")
+		}
+		a.container = container
+		return nil
+	}
+}
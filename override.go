@@ -0,0 +1,112 @@
+package fall
+
+import (
+	"reflect"
+	"slices"
+)
+
+// TestingT is the part of testing.TB used by Override, so the package does not
+// import testing.
+type TestingT interface {
+	Helper()
+	Cleanup(func())
+}
+
+// Override replaces the component name of the default container with
+// instance for the duration of the test, see Container.Override.
+func Override(t TestingT, name string, instance any) {
+	t.Helper()
+	defaultContainer.Override(t, name, instance)
+}
+
+// Override replaces the component name with instance, e.g. a mock, for the
+// duration of the test. Cached components that depend on name, directly or
+// not, are dropped so they are rebuilt with instance when resolved again;
+// components resolved before Override keep their fields. Everything is
+// restored by t.Cleanup.
+func (c *Container) Override(t TestingT, name string, instance any) {
+	t.Helper()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	previous, hadInstance := c.instances.Load(name)
+	typ, hadType := c.types[name]
+	lifetime, hadLifetime := c.lifetimes[name]
+	dropped := c.invalidate(name)
+
+	c.replace(name, instance)
+	if !hadType && instance != nil {
+		c.types[name] = reflect.TypeOf(instance)
+	}
+	c.lifetimes[name] = Singleton
+
+	t.Cleanup(func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.invalidate(name)
+		c.instances.Delete(name)
+		c.order = slices.DeleteFunc(c.order, func(n string) bool { return n == name })
+		if hadInstance {
+			c.replace(name, previous)
+		}
+		if hadType {
+			c.types[name] = typ
+		} else {
+			delete(c.types, name)
+		}
+		if hadLifetime {
+			c.lifetimes[name] = lifetime
+		} else {
+			delete(c.lifetimes, name)
+		}
+		for _, d := range dropped {
+			d.container.locked(c, func() { d.container.replace(d.name, d.instance) })
+		}
+	})
+}
+
+type droppedInstance struct {
+	dependent
+	instance any
+}
+
+// replace stores instance under name without touching its type. c.mu must be held.
+func (c *Container) replace(name string, instance any) {
+	if _, loaded := c.instances.Swap(name, instance); !loaded {
+		c.order = append(c.order, name)
+	}
+}
+
+// invalidate drops the cached instances injected with name, directly or not,
+// including those of child containers, and returns them. c.mu must be held.
+func (c *Container) invalidate(name string) []droppedInstance {
+	var dropped []droppedInstance
+	visited := map[dependent]bool{dependentOf(c, name): true}
+	queue := slices.Clone(c.dependents[name])
+	for len(queue) > 0 {
+		d := queue[0]
+		queue = queue[1:]
+		if visited[d] {
+			continue
+		}
+		visited[d] = true
+		d.container.locked(c, func() {
+			if instance, ok := d.container.instances.LoadAndDelete(d.name); ok {
+				dropped = append(dropped, droppedInstance{d, instance})
+				d.container.order = slices.DeleteFunc(d.container.order, func(n string) bool { return n == d.name })
+			}
+			queue = append(queue, d.container.dependents[d.name]...)
+		})
+	}
+	return dropped
+}
+
+// locked runs fn holding c.mu unless c is held, whose mu the caller holds.
+// Child containers are locked after their parent only here, in tests.
+func (c *Container) locked(held *Container, fn func()) {
+	if c != held {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+	}
+	fn()
+}
@@ -0,0 +1,84 @@
+package fall
+
+import "testing"
+
+func TestOverrideCleanup(t *testing.T) {
+	original := &repository{name: "original"}
+	c := NewContainer()
+	c.Register("repository", func() (any, error) { return original, nil }, As[*repository]())
+	c.Register("service", func() (any, error) { return &scopedService{}, nil }, As[*scopedService]())
+	c.Register("transient", func() (any, error) { return &scopedService{}, nil }, As[*scopedService](), WithLifetime(Transient))
+
+	before := GetNamedFrom[*scopedService](c, "service")
+	if before.Error != nil {
+		t.Fatal(before.Error)
+	}
+
+	mock := &repository{name: "mock"}
+	t.Run("dependents", func(t *testing.T) {
+		c.Override(t, "repository", mock)
+
+		for _, name := range []string{"service", "transient"} {
+			got := GetNamedFrom[*scopedService](c, name)
+			if got.Error != nil {
+				t.Fatal(got.Error)
+			}
+			if got.Value.Repository != mock {
+				t.Errorf("%s.Repository is not the mock", name)
+			}
+		}
+		if before.Value.Repository != original {
+			t.Error("components resolved before Override must keep their fields")
+		}
+	})
+
+	t.Run("new component", func(t *testing.T) {
+		c.Override(t, "missing", mock)
+		if got := GetNamedFrom[*repository](c, "missing"); got.Value != mock {
+			t.Errorf("missing = %v, want the mock", got.Value)
+		}
+	})
+
+	after := GetNamedFrom[*scopedService](c, "service")
+	if after.Error != nil {
+		t.Fatal(after.Error)
+	}
+	if after.Value != before.Value {
+		t.Error("cleanup did not restore the cached service")
+	}
+	if repository := GetNamedFrom[*repository](c, "repository"); repository.Value != original {
+		t.Error("cleanup did not restore the repository")
+	}
+	if missing := GetNamedFrom[*repository](c, "missing"); missing.Error == nil {
+		t.Error("cleanup did not remove a component added by Override")
+	}
+}
+
+func TestOverrideInvalidatesChildContainers(t *testing.T) {
+	leaf := &repository{name: "leaf"}
+	parent := NewContainer()
+	parent.Register("repository", func() (any, error) { return leaf, nil }, As[*repository]())
+	child := parent.NewChild()
+	child.Register("service", func() (any, error) { return &scopedService{}, nil }, As[*scopedService]())
+
+	before := GetNamedFrom[*scopedService](child, "service")
+	if before.Error != nil {
+		t.Fatal(before.Error)
+	}
+
+	mock := &repository{name: "mock"}
+	t.Run("override", func(t *testing.T) {
+		parent.Override(t, "repository", mock)
+		got := GetNamedFrom[*scopedService](child, "service")
+		if got.Error != nil {
+			t.Fatal(got.Error)
+		}
+		if got.Value.Repository != mock {
+			t.Errorf("service of the child holds %q, want the mock", got.Value.Repository.name)
+		}
+	})
+
+	if after := GetNamedFrom[*scopedService](child, "service"); after.Value != before.Value {
+		t.Error("cleanup did not restore the service of the child")
+	}
+}
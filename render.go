@@ -1,15 +1,18 @@
 package fall
 
 import (
+	"fmt"
 	"html/template"
 	"net/http"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 )
 
 var defaultLayout = ""
 var partialsTemplates = []string{}
+var templateCache = sync.Map{}
 
 func LoadPartials() {
 	err := scanPartials("web/views/partials")
@@ -40,8 +43,12 @@ func RenderWithLayout(w http.ResponseWriter, r *http.Request, data any, layout s
 	}
 	tmpl := []string{}
 	if templates == nil {
+		page := patternToTemplatePath(pattern)
+		if info, ok := r.Context().Value(routeContextKey).(*RouteInfo); ok && info.template != "" {
+			page = info.template
+		}
 		tmpl = []string{
-			"web/views/pages/" + patternToTemplatePath(pattern),
+			"web/views/pages/" + page,
 		}
 	} else {
 		for _, t := range templates {
@@ -53,24 +60,70 @@ func RenderWithLayout(w http.ResponseWriter, r *http.Request, data any, layout s
 		tmpl = append(tmpl, "web/views/layouts/"+layout+".html")
 	}
 
-	t, err := template.ParseFiles(tmpl...)
+	t, err := parseTemplates(RequestEnvironment(r), tmpl)
+	if err == nil {
+		t, err = t.Clone()
+	}
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		renderError(w, r, err)
 		return
 	}
+	t.Funcs(templateFuncs(r))
 	if layout == "" {
 		err = t.Execute(w, data)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			renderError(w, r, err)
 		}
 		return
 	}
 
 	err = t.ExecuteTemplate(w, layout, data)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		renderError(w, r, err)
+		return
+	}
+}
+
+// templateFuncs are bound to the request being rendered, so they are replaced
+// on a clone of the parsed templates before executing them.
+func templateFuncs(r *http.Request) template.FuncMap {
+	return template.FuncMap{
+		"url": func(name string, params ...any) (string, error) {
+			return URLFor(r, name, params...)
+		},
+	}
+}
+
+// parseTemplates reloads the files on every request in Development and caches
+// the parsed set in any other environment.
+func parseTemplates(env Environment, files []string) (*template.Template, error) {
+	if env.IsDevelopment() {
+		return newTemplate(files).ParseFiles(files...)
+	}
+	key := strings.Join(files, "|")
+	if cached, ok := templateCache.Load(key); ok {
+		return cached.(*template.Template), nil
+	}
+	t, err := newTemplate(files).ParseFiles(files...)
+	if err != nil {
+		return nil, err
+	}
+	templateCache.Store(key, t)
+	return t, nil
+}
+
+func newTemplate(files []string) *template.Template {
+	return template.New(filepath.Base(files[0])).Funcs(templateFuncs(nil))
+}
+
+// renderError shows the error details in Development and a generic message elsewhere.
+func renderError(w http.ResponseWriter, r *http.Request, err error) {
+	if RequestEnvironment(r).IsDevelopment() {
+		http.Error(w, fmt.Sprintf("%s %s\n\n%s", r.Method, r.URL.Path, err.Error()), http.StatusInternalServerError)
 		return
 	}
+	loggerFrom(r.Context()).Error("render failed", "method", r.Method, "path", r.URL.Path, "error", err)
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 }
 
 func Render(w http.ResponseWriter, r *http.Request, data any, tmpl ...string) {
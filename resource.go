@@ -0,0 +1,218 @@
+package fall
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jinzhu/inflection"
+)
+
+type resourceConfig struct {
+	name   string
+	param  string
+	only   []string
+	except []string
+	nested func(r *Router)
+}
+
+type ResourceOption func(c *resourceConfig)
+
+// Only registers just the given actions: "index", "new", "create", "show", "edit", "update" or "destroy".
+func Only(actions ...string) ResourceOption {
+	return func(c *resourceConfig) {
+		c.only = actions
+	}
+}
+
+// Except registers every action of the controller but the given ones.
+func Except(actions ...string) ResourceOption {
+	return func(c *resourceConfig) {
+		c.except = actions
+	}
+}
+
+// ResourceName replaces the route name prefix, derived from the path by default.
+func ResourceName(name string) ResourceOption {
+	return func(c *resourceConfig) {
+		c.name = name
+	}
+}
+
+// ResourceParam replaces the "id" wildcard of member routes.
+func ResourceParam(param string) ResourceOption {
+	return func(c *resourceConfig) {
+		c.param = param
+	}
+}
+
+// Nested registers routes under a member of the resource, e.g. "/posts/{post_id}".
+// The parent wildcard is named after the singular of the resource.
+func Nested(fn func(r *Router)) ResourceOption {
+	return func(c *resourceConfig) {
+		c.nested = fn
+	}
+}
+
+type resourceIndex interface {
+	Index(w http.ResponseWriter, r *http.Request)
+}
+
+type resourceNew interface {
+	New(w http.ResponseWriter, r *http.Request)
+}
+
+type resourceCreate interface {
+	Create(w http.ResponseWriter, r *http.Request)
+}
+
+type resourceShow interface {
+	Show(w http.ResponseWriter, r *http.Request)
+}
+
+type resourceEdit interface {
+	Edit(w http.ResponseWriter, r *http.Request)
+}
+
+type resourceUpdate interface {
+	Update(w http.ResponseWriter, r *http.Request)
+}
+
+type resourceDestroy interface {
+	Destroy(w http.ResponseWriter, r *http.Request)
+}
+
+type resourceAction struct {
+	name     string
+	methods  []string
+	path     string
+	template string
+	handler  func(ctrl any) (http.HandlerFunc, bool)
+}
+
+var resourceActions = []resourceAction{
+	{"index", []string{http.MethodGet}, "", "index.html", func(ctrl any) (http.HandlerFunc, bool) {
+		c, ok := ctrl.(resourceIndex)
+		if !ok {
+			return nil, false
+		}
+		return c.Index, true
+	}},
+	{"new", []string{http.MethodGet}, "/new", "new.html", func(ctrl any) (http.HandlerFunc, bool) {
+		c, ok := ctrl.(resourceNew)
+		if !ok {
+			return nil, false
+		}
+		return c.New, true
+	}},
+	{"create", []string{http.MethodPost}, "", "new.html", func(ctrl any) (http.HandlerFunc, bool) {
+		c, ok := ctrl.(resourceCreate)
+		if !ok {
+			return nil, false
+		}
+		return c.Create, true
+	}},
+	{"show", []string{http.MethodGet}, "/{%s}", "show.html", func(ctrl any) (http.HandlerFunc, bool) {
+		c, ok := ctrl.(resourceShow)
+		if !ok {
+			return nil, false
+		}
+		return c.Show, true
+	}},
+	{"edit", []string{http.MethodGet}, "/{%s}/edit", "edit.html", func(ctrl any) (http.HandlerFunc, bool) {
+		c, ok := ctrl.(resourceEdit)
+		if !ok {
+			return nil, false
+		}
+		return c.Edit, true
+	}},
+	{"update", []string{http.MethodPut, http.MethodPatch}, "/{%s}", "edit.html", func(ctrl any) (http.HandlerFunc, bool) {
+		c, ok := ctrl.(resourceUpdate)
+		if !ok {
+			return nil, false
+		}
+		return c.Update, true
+	}},
+	{"destroy", []string{http.MethodDelete}, "/{%s}", "", func(ctrl any) (http.HandlerFunc, bool) {
+		c, ok := ctrl.(resourceDestroy)
+		if !ok {
+			return nil, false
+		}
+		return c.Destroy, true
+	}},
+}
+
+// Resource registers the REST routes for every Index, New, Create, Show, Edit,
+// Update and Destroy method of ctrl, following the Render template conventions:
+//
+//	GET       /posts            Index    posts/index.html
+//	GET       /posts/new        New      posts/new.html
+//	POST      /posts            Create   posts/new.html
+//	GET       /posts/{id}       Show     posts/show.html
+//	GET       /posts/{id}/edit  Edit     posts/edit.html
+//	PUT/PATCH /posts/{id}       Update   posts/edit.html
+//	DELETE    /posts/{id}       Destroy
+//
+// Routes are named "posts.index", "posts.show" and so on. Templates of nested
+// resources leave out the parent wildcards, e.g. posts/comments/index.html.
+func (r *Router) Resource(path string, ctrl any, opts ...ResourceOption) {
+	resource := strings.Trim(path, "/")
+	config := resourceConfig{
+		name:  strings.ReplaceAll(resource, "/", "."),
+		param: "id",
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	name := config.name
+	if r.resourceName != "" {
+		name = r.resourceName + "." + name
+	}
+
+	// Wildcards do recurso pai não fazem parte do diretório do template
+	dirs := []string{}
+	for _, segment := range strings.Split(strings.Trim(r.path(path), "/"), "/") {
+		if segment != "" && !wildcardRegex.MatchString(segment) {
+			dirs = append(dirs, segment)
+		}
+	}
+	dir := strings.Join(dirs, "/")
+
+	registered := 0
+	for _, action := range resourceActions {
+		if len(config.only) > 0 && !slices.Contains(config.only, action.name) {
+			continue
+		}
+		if slices.Contains(config.except, action.name) {
+			continue
+		}
+		handler, ok := action.handler(ctrl)
+		if !ok {
+			continue
+		}
+		actionPath := path + action.path
+		if strings.Contains(action.path, "%s") {
+			actionPath = path + fmt.Sprintf(action.path, config.param)
+		}
+		route := r.Match(action.methods, actionPath, handler).Name(name + "." + action.name)
+		if action.template != "" {
+			for _, info := range route.infos {
+				info.template = filepath.Join(dir, action.template)
+			}
+		}
+		registered++
+	}
+	if registered == 0 {
+		panic(fmt.Errorf("resource %s: %T has no resource actions", path, ctrl))
+	}
+
+	if config.nested != nil {
+		parentParam := inflection.Singular(resource[strings.LastIndex(resource, "/")+1:]) + "_id"
+		r.Group(fmt.Sprintf("%s/{%s}", path, parentParam), func(nested *Router) {
+			nested.resourceName = name
+			config.nested(nested)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package fall
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type postsController struct{}
+
+func (postsController) Index(w http.ResponseWriter, r *http.Request)  { w.Write([]byte("index")) }
+func (postsController) New(w http.ResponseWriter, r *http.Request)    { w.Write([]byte("new")) }
+func (postsController) Create(w http.ResponseWriter, r *http.Request) { w.Write([]byte("create")) }
+func (postsController) Show(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("show " + r.PathValue("id")))
+}
+func (postsController) Edit(w http.ResponseWriter, r *http.Request)    { w.Write([]byte("edit")) }
+func (postsController) Update(w http.ResponseWriter, r *http.Request)  { w.Write([]byte("update")) }
+func (postsController) Destroy(w http.ResponseWriter, r *http.Request) { w.Write([]byte("destroy")) }
+
+type commentsController struct{}
+
+func (commentsController) Index(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("comments of " + r.PathValue("post_id")))
+}
+
+func resourceRoutes(r *Router) []string {
+	var routes []string
+	for _, route := range r.Routes() {
+		routes = append(routes, route.Method+" "+route.Pattern+" "+route.Name+" "+route.template)
+	}
+	return routes
+}
+
+func TestResourceRoutes(t *testing.T) {
+	r := NewRouter("")
+	r.Resource("/posts", postsController{}, Nested(func(r *Router) {
+		r.Resource("/comments", commentsController{})
+	}))
+
+	want := []string{
+		"GET /posts posts.index posts/index.html",
+		"GET /posts/new posts.new posts/new.html",
+		"POST /posts posts.create posts/new.html",
+		"GET /posts/{id} posts.show posts/show.html",
+		"GET /posts/{id}/edit posts.edit posts/edit.html",
+		"PUT /posts/{id} posts.update posts/edit.html",
+		"PATCH /posts/{id} posts.update posts/edit.html",
+		"DELETE /posts/{id} posts.destroy ",
+		"GET /posts/{post_id}/comments posts.comments.index posts/comments/index.html",
+	}
+	if got := resourceRoutes(r); !slices.Equal(got, want) {
+		t.Errorf("routes =\n%q\nwant\n%q", got, want)
+	}
+
+	tests := map[string]string{
+		"GET /posts/new":        "new",
+		"GET /posts/7":          "show 7",
+		"PATCH /posts/7":        "update",
+		"DELETE /posts/7":       "destroy",
+		"GET /posts/7/comments": "comments of 7",
+		"POST /posts":           "create",
+		"GET /posts/7/edit":     "edit",
+		"GET /posts":            "index",
+	}
+	for request, body := range tests {
+		method, path, _ := strings.Cut(request, " ")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		if w.Body.String() != body {
+			t.Errorf("%s = %q, want %q", request, w.Body.String(), body)
+		}
+	}
+}
+
+func TestResourceOptions(t *testing.T) {
+	r := NewRouter("")
+	r.Resource("/posts", postsController{}, Only("index", "show"), ResourceName("articles"), ResourceParam("slug"))
+	r.Resource("/drafts", postsController{}, Except("new", "create", "edit", "update", "destroy"))
+
+	want := []string{
+		"GET /posts articles.index posts/index.html",
+		"GET /posts/{slug} articles.show posts/show.html",
+		"GET /drafts drafts.index drafts/index.html",
+		"GET /drafts/{id} drafts.show drafts/show.html",
+	}
+	if got := resourceRoutes(r); !slices.Equal(got, want) {
+		t.Errorf("routes =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestResourceWithoutActions(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Resource accepted a controller without actions")
+		}
+	}()
+	NewRouter("").Resource("/posts", struct{}{})
+}
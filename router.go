@@ -2,8 +2,8 @@ package fall
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log/slog"
 	"net/http"
 	"slices"
 	"strings"
@@ -13,21 +13,34 @@ type Middleware func(http.Handler) http.Handler
 
 type Router struct {
 	*http.ServeMux
-	prefix string
-	chain  []Middleware
+	prefix           string
+	chain            []Middleware
+	registry         *routeRegistry
+	host             *hostPattern
+	resourceName     string
+	version          *apiVersion
+	notFound         http.Handler
+	methodNotAllowed http.Handler
 }
 
 func NewRouter(prefix string, middlewares ...Middleware) *Router {
-	return &Router{
+	r := &Router{
 		prefix:   prefix,
 		ServeMux: http.NewServeMux(),
 		chain:    middlewares,
+		registry: &routeRegistry{},
 	}
+	r.registry.root = r
+	return r
 }
 
 type contextKey string
 
-const patternContextKey contextKey = "fall.pattern"
+const (
+	patternContextKey  contextKey = "fall.pattern"
+	registryContextKey contextKey = "fall.registry"
+	routeContextKey    contextKey = "fall.route"
+)
 
 func (r *Router) Use(mw ...Middleware) {
 	r.chain = append(r.chain, mw...)
@@ -36,9 +49,13 @@ func (r *Router) Use(mw ...Middleware) {
 func (r *Router) Group(prefix string, fn func(r *Router)) {
 	middlewares := slices.Clone(r.chain)
 	fn(&Router{
-		prefix:   r.path(prefix),
-		ServeMux: r.ServeMux,
-		chain:    middlewares,
+		prefix:       r.path(prefix),
+		ServeMux:     r.ServeMux,
+		chain:        middlewares,
+		registry:     r.registry,
+		host:         r.host,
+		resourceName: r.resourceName,
+		version:      r.version,
 	})
 }
 
@@ -56,46 +73,159 @@ func (r *Router) format(path string) string {
 	return "/" + path
 }
 
-func (r *Router) Get(path string, fn http.HandlerFunc, mws ...Middleware) {
-	r.handle(http.MethodGet, r.path(path), fn, mws...)
+func (r *Router) Get(path string, fn http.HandlerFunc, mws ...Middleware) *Route {
+	return r.handle(http.MethodGet, r.path(path), fn, mws...)
 }
 
-func (r *Router) Post(path string, fn http.HandlerFunc, mws ...Middleware) {
-	r.handle(http.MethodPost, r.path(path), fn, mws...)
+func (r *Router) Post(path string, fn http.HandlerFunc, mws ...Middleware) *Route {
+	return r.handle(http.MethodPost, r.path(path), fn, mws...)
 }
 
-func (r *Router) Put(path string, fn http.HandlerFunc, mws ...Middleware) {
-	r.handle(http.MethodPut, r.path(path), fn, mws...)
+func (r *Router) Put(path string, fn http.HandlerFunc, mws ...Middleware) *Route {
+	return r.handle(http.MethodPut, r.path(path), fn, mws...)
 }
 
-func (r *Router) Delete(path string, fn http.HandlerFunc, mws ...Middleware) {
-	r.handle(http.MethodDelete, r.path(path), fn, mws...)
+func (r *Router) Delete(path string, fn http.HandlerFunc, mws ...Middleware) *Route {
+	return r.handle(http.MethodDelete, r.path(path), fn, mws...)
 }
 
-func (r *Router) Patch(path string, fn http.HandlerFunc, mws ...Middleware) {
-	r.handle(http.MethodPatch, r.path(path), fn, mws...)
+func (r *Router) Patch(path string, fn http.HandlerFunc, mws ...Middleware) *Route {
+	return r.handle(http.MethodPatch, r.path(path), fn, mws...)
 }
 
-func (r *Router) Options(path string, fn http.HandlerFunc, mws ...Middleware) {
-	r.handle(http.MethodOptions, r.path(path), fn, mws...)
+func (r *Router) Options(path string, fn http.HandlerFunc, mws ...Middleware) *Route {
+	return r.handle(http.MethodOptions, r.path(path), fn, mws...)
 }
 
-func (r *Router) handle(method, path string, fn http.HandlerFunc, mws ...Middleware) {
-	fullPattern := fmt.Sprintf("%s %s", method, path)
-	slog.Info(fullPattern)
-	r.Handle(fmt.Sprintf("%s %s", method, path), r.wrap(fn, fullPattern, mws...))
+func (r *Router) Head(path string, fn http.HandlerFunc, mws ...Middleware) *Route {
+	return r.handle(http.MethodHead, r.path(path), fn, mws...)
 }
 
-func (r *Router) wrap(fn http.HandlerFunc, routePattern string, mws ...Middleware) (out http.Handler) {
-	baseHandler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
-		ctx := context.WithValue(req.Context(), patternContextKey, routePattern)
-		req = req.WithContext(ctx)
-		fn(w, req)
-	})
+// Any registers fn for every method on path.
+func (r *Router) Any(path string, fn http.HandlerFunc, mws ...Middleware) *Route {
+	return r.handle("", r.path(path), fn, mws...)
+}
+
+// Match registers fn for each of methods on path.
+func (r *Router) Match(methods []string, path string, fn http.HandlerFunc, mws ...Middleware) *Route {
+	route := &Route{registry: r.registry}
+	for _, method := range methods {
+		route.infos = append(route.infos, r.handle(strings.ToUpper(method), r.path(path), fn, mws...).infos...)
+	}
+	return route
+}
 
-	out, mwss := http.Handler(baseHandler), append(r.chain, mws...)
+// Mount serves handler, e.g. a file server or a *Router built elsewhere, for every
+// method under prefix. The prefix is stripped from the request path before
+// handler runs and the middleware chain of r applies as for any other route.
+func (r *Router) Mount(prefix string, handler http.Handler, mws ...Middleware) *Route {
+	path := strings.TrimSuffix(r.path(prefix), "/")
+	fullPattern := path + "/"
+	r.registry.log().Info(fullPattern)
+	info := &RouteInfo{
+		Host:        r.host.String(),
+		Pattern:     fullPattern,
+		Handler:     handlerName(handler),
+		Controller:  r.registry.controller,
+		Middlewares: middlewareNames(append(slices.Clone(r.chain), mws...)),
+		Mounted:     true,
+	}
+	stripped := http.StripPrefix(path, handler)
+	if !r.register(fullPattern, r.wrap(stripped.ServeHTTP, fullPattern, info, mws...), info) {
+		return &Route{registry: r.registry, infos: []*RouteInfo{info}}
+	}
+
+	if router, ok := handler.(*Router); ok {
+		info.mounted = router
+	}
+	r.registry.add(info)
+	return &Route{registry: r.registry, infos: []*RouteInfo{info}}
+}
+
+func (r *Router) handle(method, rawPath string, fn http.HandlerFunc, mws ...Middleware) *Route {
+	path, constraints, err := parseConstraints(rawPath)
+	if err != nil {
+		info := &RouteInfo{Host: r.host.String(), Method: method, Pattern: rawPath, Controller: r.registry.controller}
+		r.registry.addError(fmt.Errorf("invalid route %s: %w", info.describe(), err))
+		return &Route{registry: r.registry, infos: []*RouteInfo{info}}
+	}
+	fullPattern := strings.TrimSpace(fmt.Sprintf("%s %s", method, path))
+	r.registry.log().Info(fullPattern)
+	info := &RouteInfo{
+		Host:        r.host.String(),
+		Method:      method,
+		Pattern:     path,
+		Handler:     funcName(fn),
+		Controller:  r.registry.controller,
+		Middlewares: middlewareNames(append(slices.Clone(r.chain), mws...)),
+	}
+	for _, param := range constraints {
+		if info.Constraints == nil {
+			info.Constraints = make(map[string]string)
+		}
+		info.Constraints[param.name] = param.constraint
+	}
+	handler := r.wrap(fn, fullPattern, info, mws...)
+	if len(constraints) > 0 {
+		handler = r.validateParams(constraints, handler)
+	}
+	if r.version == nil {
+		if !r.registerRoute(fullPattern, handler, info) {
+			return &Route{registry: r.registry, infos: []*RouteInfo{info}}
+		}
+	} else {
+		if !r.register(fullPattern, handler, info) {
+			return &Route{registry: r.registry, infos: []*RouteInfo{info}}
+		}
+		info.Version = r.version.name
+		info.Deprecated = !r.version.deprecated.IsZero()
+		r.registerVersioned(info, path, handler)
+	}
+	r.registry.add(info)
+	return &Route{registry: r.registry, infos: []*RouteInfo{info}}
+}
+
+// register adds handler to the mux. An invalid pattern or one conflicting with
+// another route is recorded, see Err, instead of panicking.
+func (r *Router) register(pattern string, handler http.Handler, info *RouteInfo) (ok bool) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			r.registry.addError(r.registry.conflict(info, recovered))
+			ok = false
+		}
+	}()
+	r.Handle(pattern, routeHandler{Handler: handler, info: info})
+	return true
+}
+
+// Err reports every route that could not be registered because its pattern is
+// invalid, duplicated or conflicting with another route, including the routes
+// of mounted routers. NewApp and App.Run return it.
+func (r *Router) Err() error {
+	r.registry.mu.RLock()
+	errs := slices.Clone(r.registry.errs)
+	for _, route := range r.registry.routes {
+		if route.mounted != nil {
+			errs = append(errs, route.mounted.Err())
+		}
+	}
+	r.registry.mu.RUnlock()
+	return errors.Join(errs...)
+}
+
+// wrap applies the middlewares to fn. The route pattern and info are stored in
+// the request context before the first middleware of the route runs.
+func (r *Router) wrap(fn http.HandlerFunc, routePattern string, info *RouteInfo, mws ...Middleware) (out http.Handler) {
+	out, mwss := http.Handler(fn), append(r.chain, mws...)
 	for i := len(mwss) - 1; i >= 0; i-- {
 		out = mwss[i](out)
 	}
-	return out
+
+	next := out
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := context.WithValue(req.Context(), patternContextKey, routePattern)
+		ctx = context.WithValue(ctx, registryContextKey, r.registry)
+		ctx = context.WithValue(ctx, routeContextKey, info)
+		next.ServeHTTP(w, req.WithContext(ctx))
+	})
 }
@@ -0,0 +1,385 @@
+package fall
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"maps"
+	"net/http"
+	"path"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// RouteInfo describes a route registered on a Router. Method is empty for
+// routes that accept any method.
+type RouteInfo struct {
+	Name        string            `json:"name,omitempty"`
+	Host        string            `json:"host,omitempty"`
+	Method      string            `json:"method"`
+	Pattern     string            `json:"pattern"`
+	Version     string            `json:"version,omitempty"`
+	Constraints map[string]string `json:"constraints,omitempty"`
+	Handler     string            `json:"handler"`
+	Controller  string            `json:"controller,omitempty"`
+	Middlewares []string          `json:"middlewares"`
+	Summary     string            `json:"summary,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+	Deprecated  bool              `json:"deprecated,omitempty"`
+	Roles       []string          `json:"roles,omitempty"`
+	RateLimit   string            `json:"rateLimit,omitempty"`
+	Metadata    map[string]any    `json:"metadata,omitempty"`
+	Mounted     bool              `json:"mounted,omitempty"`
+	Request     reflect.Type      `json:"-"`
+	Response    reflect.Type      `json:"-"`
+	mounted     *Router
+	template    string
+}
+
+// MarshalJSON writes the request and response types by name.
+func (ri RouteInfo) MarshalJSON() ([]byte, error) {
+	type routeInfo RouteInfo
+	return json.Marshal(struct {
+		routeInfo
+		Request  string `json:"request,omitempty"`
+		Response string `json:"response,omitempty"`
+	}{routeInfo(ri), typeName(ri.Request), typeName(ri.Response)})
+}
+
+func typeName(typ reflect.Type) string {
+	if typ == nil {
+		return ""
+	}
+	return typ.String()
+}
+
+type routeRegistry struct {
+	mu        sync.RWMutex
+	routes    []*RouteInfo
+	names     map[string]*RouteInfo
+	root      *Router
+	fallbacks []*Router
+	hosts     []*Router
+	versioned map[string]*versionedRoute
+	// controller is the type of the controller being configured, see App.SetControllers.
+	controller string
+	errs       []error
+	// logger logs the registered routes, the App one under NewApp.
+	logger *slog.Logger
+}
+
+func (rr *routeRegistry) log() *slog.Logger {
+	if rr.logger == nil {
+		return slog.Default()
+	}
+	return rr.logger
+}
+
+// Route is returned when registering a route and names or describes it.
+type Route struct {
+	registry *routeRegistry
+	infos    []*RouteInfo
+}
+
+// Name registers the route for reverse URL generation with URL and the "url"
+// template function. Names must be unique within a router.
+func (rt *Route) Name(name string) *Route {
+	rt.registry.mu.Lock()
+	defer rt.registry.mu.Unlock()
+	if existing, ok := rt.registry.names[name]; ok {
+		panic(fmt.Errorf("route name %q already used by %s %s", name, existing.Method, existing.Pattern))
+	}
+	if rt.registry.names == nil {
+		rt.registry.names = make(map[string]*RouteInfo)
+	}
+	for _, info := range rt.infos {
+		info.Name = name
+	}
+	if len(rt.infos) > 0 {
+		rt.registry.names[name] = rt.infos[0]
+	}
+	return rt
+}
+
+func (rt *Route) Summary(summary string) *Route {
+	return rt.each(func(info *RouteInfo) { info.Summary = summary })
+}
+
+func (rt *Route) Tags(tags ...string) *Route {
+	return rt.each(func(info *RouteInfo) { info.Tags = append(info.Tags, tags...) })
+}
+
+func (rt *Route) Deprecated() *Route {
+	return rt.each(func(info *RouteInfo) { info.Deprecated = true })
+}
+
+// Roles records the roles allowed to call the route, for authorization middleware to enforce.
+func (rt *Route) Roles(roles ...string) *Route {
+	return rt.each(func(info *RouteInfo) { info.Roles = append(info.Roles, roles...) })
+}
+
+// RateLimit records the rate-limit class of the route, for rate-limiting middleware to enforce.
+func (rt *Route) RateLimit(class string) *Route {
+	return rt.each(func(info *RouteInfo) { info.RateLimit = class })
+}
+
+// Request records the type of the request body from a value of it, e.g. CreatePostDTO{}.
+func (rt *Route) Request(v any) *Route {
+	return rt.each(func(info *RouteInfo) { info.Request = reflect.TypeOf(v) })
+}
+
+// Response records the type of the response body from a value of it.
+func (rt *Route) Response(v any) *Route {
+	return rt.each(func(info *RouteInfo) { info.Response = reflect.TypeOf(v) })
+}
+
+func (rt *Route) Meta(key string, value any) *Route {
+	return rt.each(func(info *RouteInfo) {
+		if info.Metadata == nil {
+			info.Metadata = make(map[string]any)
+		}
+		info.Metadata[key] = value
+	})
+}
+
+func (rt *Route) each(fn func(info *RouteInfo)) *Route {
+	rt.registry.mu.Lock()
+	defer rt.registry.mu.Unlock()
+	for _, info := range rt.infos {
+		fn(info)
+	}
+	return rt
+}
+
+// CurrentRoute returns the route matched by the request. It is available to
+// every middleware of the route and, under App.Run, to the App middlewares,
+// e.g. to enforce RouteInfo.Roles. Under a Mount it is the mounted route until
+// a route of the mounted router matches.
+func CurrentRoute(r *http.Request) Result[RouteInfo] {
+	info := GetContextValue[*RouteInfo](r, routeContextKey)
+	if info.Error != nil {
+		return NewResult(RouteInfo{}, info.Error)
+	}
+	return NewResult(*info.Value, nil)
+}
+
+// routeHandler is registered in the mux for every route, so the route serving a
+// request is known before the middlewares of the App run.
+type routeHandler struct {
+	http.Handler
+	info *RouteInfo
+}
+
+func (h routeHandler) routeFor(req *http.Request) *RouteInfo {
+	if route, ok := h.Handler.(*versionedRoute); ok {
+		return route.routeFor(req)
+	}
+	return h.info
+}
+
+// matchRoute stores the route serving the request in its context for the
+// middlewares that run before the router, see CurrentRoute.
+func (r *Router) matchRoute(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		handler, _ := r.target(req).ServeMux.Handler(req)
+		if route, ok := handler.(routeHandler); ok {
+			if info := route.routeFor(req); info != nil {
+				req = req.WithContext(context.WithValue(req.Context(), routeContextKey, info))
+			}
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+func (rr *routeRegistry) add(route *RouteInfo) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	rr.routes = append(rr.routes, route)
+}
+
+func (rr *routeRegistry) remove(route *RouteInfo) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	rr.routes = slices.DeleteFunc(rr.routes, func(r *RouteInfo) bool { return r == route })
+}
+
+// list also expands the routes of mounted routers under their mount point.
+func (rr *routeRegistry) list() []RouteInfo {
+	rr.mu.RLock()
+	defer rr.mu.RUnlock()
+	routes := make([]RouteInfo, 0, len(rr.routes))
+	for _, route := range rr.routes {
+		info := *route
+		info.Middlewares = slices.Clone(route.Middlewares)
+		info.Metadata = maps.Clone(route.Metadata)
+		info.Tags = slices.Clone(route.Tags)
+		info.Roles = slices.Clone(route.Roles)
+		info.Constraints = maps.Clone(route.Constraints)
+		routes = append(routes, info)
+		if route.mounted == nil {
+			continue
+		}
+		prefix := strings.TrimSuffix(route.Pattern, "/")
+		for _, child := range route.mounted.registry.list() {
+			child.Pattern = prefix + child.Pattern
+			child.Middlewares = append(slices.Clone(route.Middlewares), child.Middlewares...)
+			if child.Host == "" {
+				child.Host = route.Host
+			}
+			routes = append(routes, child)
+		}
+	}
+	return routes
+}
+
+// Routes returns every route registered on the router and its groups, in registration order.
+func (r *Router) Routes() []RouteInfo {
+	return r.registry.list()
+}
+
+// Routes returns every route of the App, including the App middlewares.
+func (a *App) Routes() []RouteInfo {
+	appMiddlewares := middlewareNames(a.middlewares)
+	routes := a.router.Routes()
+	for i := range routes {
+		routes[i].Middlewares = append(slices.Clone(appMiddlewares), routes[i].Middlewares...)
+	}
+	return routes
+}
+
+func (rr *routeRegistry) addError(err error) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	rr.errs = append(rr.errs, err)
+}
+
+// conflict explains why the mux refused info, naming the controllers that
+// registered both routes when they are known.
+func (rr *routeRegistry) conflict(info *RouteInfo, recovered any) error {
+	pattern := info.muxPattern()
+	if !patternsConflict("", pattern) {
+		rr.mu.RLock()
+		defer rr.mu.RUnlock()
+		for _, existing := range rr.routes {
+			if existing.Host != info.Host || !patternsConflict(existing.muxPattern(), pattern) {
+				continue
+			}
+			if existing.muxPattern() == pattern {
+				if existing.Controller == "" {
+					return fmt.Errorf("duplicate route %s", info.describe())
+				}
+				return fmt.Errorf("duplicate route %s, already registered%s", info.describe(), existing.registeredBy())
+			}
+			return fmt.Errorf("route %s conflicts with %s", info.describe(), existing.describe())
+		}
+	}
+	return fmt.Errorf("invalid route %s: %v", info.describe(), recovered)
+}
+
+// patternsConflict reports whether a ServeMux refuses b after a, or b alone when a is empty.
+func patternsConflict(a, b string) (conflict bool) {
+	mux := http.NewServeMux()
+	defer func() {
+		conflict = recover() != nil
+	}()
+	if a != "" {
+		mux.Handle(a, http.NotFoundHandler())
+	}
+	mux.Handle(b, http.NotFoundHandler())
+	return false
+}
+
+func (ri *RouteInfo) muxPattern() string {
+	return strings.TrimSpace(ri.Method + " " + ri.Pattern)
+}
+
+func (ri *RouteInfo) describe() string {
+	route := ri.muxPattern()
+	if ri.Host != "" {
+		route += " on " + ri.Host
+	}
+	return route + ri.registeredBy()
+}
+
+func (ri *RouteInfo) registeredBy() string {
+	if ri.Controller == "" {
+		return ""
+	}
+	return " by " + ri.Controller
+}
+
+func handlerName(handler http.Handler) string {
+	if fn, ok := handler.(http.HandlerFunc); ok {
+		return funcName(fn)
+	}
+	return fmt.Sprintf("%T", handler)
+}
+
+func middlewareNames(middlewares []Middleware) []string {
+	names := make([]string, len(middlewares))
+	for i, middleware := range middlewares {
+		names[i] = funcName(middleware)
+	}
+	return names
+}
+
+// funcName turns "github.com/org/app/controllers.(*Users).List-fm" into "controllers.(*Users).List".
+func funcName(fn any) string {
+	val := reflect.ValueOf(fn)
+	if val.Kind() != reflect.Func || val.IsNil() {
+		return ""
+	}
+	name := runtime.FuncForPC(val.Pointer()).Name()
+	name = strings.TrimSuffix(name, "-fm")
+	return path.Base(name)
+}
+
+var routesTemplate = template.Must(template.New("routes").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Routes</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: 0.4em 0.8em; border-bottom: 1px solid #ddd; vertical-align: top; }
+code { font-size: 0.9em; }
+</style>
+</head>
+<body>
+<h1>Routes ({{len .}})</h1>
+<table>
+<tr><th>Host</th><th>Method</th><th>Pattern</th><th>Name</th><th>Handler</th><th>Middlewares</th><th>Types</th><th>Metadata</th></tr>
+{{range .}}<tr>
+<td>{{.Host}}</td>
+<td>{{.Method}}</td>
+<td><code>{{.Pattern}}</code>{{with .Version}} ({{.}}){{end}}{{range $name, $constraint := .Constraints}}<br>{{$name}}: {{$constraint}}{{end}}</td>
+<td>{{.Name}}{{if .Deprecated}} <em>(deprecated)</em>{{end}}{{with .Summary}}<br>{{.}}{{end}}</td>
+<td><code>{{.Handler}}</code>{{with .Controller}}<br><code>{{.}}</code>{{end}}</td>
+<td>{{range .Middlewares}}<code>{{.}}</code><br>{{end}}</td>
+<td>{{with .Request}}in: <code>{{.}}</code><br>{{end}}{{with .Response}}out: <code>{{.}}</code>{{end}}</td>
+<td>{{with .Tags}}tags: {{range .}}{{.}} {{end}}<br>{{end}}{{with .Roles}}roles: {{range .}}{{.}} {{end}}<br>{{end}}{{with .RateLimit}}rate limit: {{.}}<br>{{end}}{{range $key, $value := .Metadata}}{{$key}}: {{$value}}<br>{{end}}</td>
+</tr>{{end}}
+</table>
+</body>
+</html>
+`))
+
+// handleRoutes serves the route explorer as JSON when asked for it and as HTML otherwise.
+func (a *App) handleRoutes(w http.ResponseWriter, r *http.Request) {
+	routes := a.Routes()
+	if r.URL.Query().Get("format") == "json" || strings.Contains(r.Header.Get("Accept"), "application/json") {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(routes)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := routesTemplate.Execute(w, routes); err != nil {
+		renderError(w, r, err)
+	}
+}
@@ -0,0 +1,66 @@
+package fall
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestCurrentRouteInAppMiddlewares(t *testing.T) {
+	var mu sync.Mutex
+	var seen []string
+	record := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := CurrentRoute(r)
+			mu.Lock()
+			if route.Error != nil {
+				seen = append(seen, "none")
+			} else {
+				seen = append(seen, route.Value.Name+"@"+route.Value.Version+" "+strings.Join(route.Value.Roles, ","))
+			}
+			mu.Unlock()
+			next.ServeHTTP(w, r)
+		})
+	}
+	app, url, cancel, done := runApp(t, NewContainer(), WithMiddleware(record))
+	defer func() {
+		cancel()
+		<-done
+	}()
+	router := app.GetRouter()
+	router.Delete("/posts/{id}", noopHandler).Name("posts.destroy").Roles("admin")
+	router.Get("/users", noopHandler).Name("users")
+	router.Group("/api", func(r *Router) {
+		r.Version("v2", func(r *Router) {
+			r.Get("/users", noopHandler).Name("api.users")
+		})
+	})
+
+	requests := []struct {
+		method, path, version string
+	}{
+		{method: http.MethodDelete, path: "/posts/1"},
+		{method: http.MethodHead, path: "/users"},
+		{method: http.MethodGet, path: "/api/users", version: "v2"},
+		{method: http.MethodGet, path: "/missing"},
+	}
+	for _, r := range requests {
+		req, _ := http.NewRequest(r.method, url+r.path, nil)
+		if r.version != "" {
+			req.Header.Set("API-Version", r.version)
+		}
+		resp, err := testClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	want := []string{"posts.destroy@ admin", "users@ ", "api.users@v2 ", "none"}
+	mu.Lock()
+	defer mu.Unlock()
+	if strings.Join(seen, "|") != strings.Join(want, "|") {
+		t.Errorf("routes = %q, want %q", seen, want)
+	}
+}
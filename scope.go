@@ -0,0 +1,183 @@
+package fall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sync"
+)
+
+const scopeContextKey contextKey = "fall.scope"
+
+// Scope holds the request-scoped components of one request, resolved from the
+// container that created it.
+type Scope struct {
+	container *Container
+	mu        sync.Mutex
+	resolving sync.Mutex
+	instances map[string]any
+	order     []string
+}
+
+// NewScope creates a Scope of the default container.
+func NewScope() *Scope {
+	return defaultContainer.NewScope()
+}
+
+func (c *Container) NewScope() *Scope {
+	return &Scope{container: c, instances: make(map[string]any)}
+}
+
+func (s *Scope) get(name string) (any, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	instance, ok := s.instances[name]
+	return instance, ok
+}
+
+func (s *Scope) add(name string, instance any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.instances[name] = instance
+	s.order = append(s.order, name)
+}
+
+// Close stops every Stoppable or io.Closer of the scope in reverse order of
+// construction and empties it.
+func (s *Scope) Close(ctx context.Context) error {
+	s.mu.Lock()
+	instances, order := s.instances, s.order
+	s.instances, s.order = make(map[string]any), nil
+	s.mu.Unlock()
+
+	var errs []error
+	for i := len(order) - 1; i >= 0; i-- {
+		var err error
+		switch component := instances[order[i]].(type) {
+		case Stoppable:
+			err = component.Stop(ctx)
+		case io.Closer:
+			err = component.Close()
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("stop failed for %s: %w", order[i], err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WithScope returns a copy of ctx carrying scope.
+func WithScope(ctx context.Context, scope *Scope) context.Context {
+	return context.WithValue(ctx, scopeContextKey, scope)
+}
+
+func scopeFrom(ctx context.Context) *Scope {
+	scope, _ := ctx.Value(scopeContextKey).(*Scope)
+	return scope
+}
+
+// RequestScope gives each request its own Scope of the default container and
+// closes it when the request ends.
+func RequestScope(next http.Handler) http.Handler {
+	return defaultContainer.RequestScope(next)
+}
+
+// RequestScope gives each request its own Scope of c and closes it when the
+// request ends. The App installs it for its container.
+func (c *Container) RequestScope(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if scopeFrom(r.Context()) != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		scope := c.NewScope()
+		defer func() {
+			if err := scope.Close(context.WithoutCancel(r.Context())); err != nil {
+				loggerFrom(r.Context()).Error("request scope disposal failed", "error", err)
+			}
+		}()
+		next.ServeHTTP(w, r.WithContext(WithScope(r.Context(), scope)))
+	})
+}
+
+// containerFor returns the container of the scope of ctx, or the default one.
+func containerFor(ctx context.Context) (*Container, *Scope) {
+	scope := scopeFrom(ctx)
+	if scope == nil {
+		return defaultContainer, nil
+	}
+	return scope.container, scope
+}
+
+// ResolveScoped resolves name within the request scope of ctx.
+func ResolveScoped(ctx context.Context, name string) (any, error) {
+	c, scope := containerFor(ctx)
+	return c.resolveScoped(name, scope)
+}
+
+func (c *Container) resolveScoped(name string, scope *Scope) (any, error) {
+	defer lockResolution(c, scope)()
+	return c.resolve(name, scope, nil)
+}
+
+// lockResolution locks scope while resolving within it, so concurrent requests
+// build their components in parallel, or c outside of a scope.
+func lockResolution(c *Container, scope *Scope) func() {
+	if scope == nil {
+		c.mu.Lock()
+		return c.mu.Unlock
+	}
+	scope.resolving.Lock()
+	return scope.resolving.Unlock
+}
+
+// GetScoped resolves the component of type T within the request scope of ctx.
+func GetScoped[T any](ctx context.Context) Result[T] {
+	c, scope := containerFor(ctx)
+	return getScoped[T](c, scope)
+}
+
+func getScoped[T any](c *Container, scope *Scope) Result[T] {
+	defer lockResolution(c, scope)()
+	instance, err := c.resolveType(reflect.TypeFor[T](), scope, nil)
+	return typedResult[T](instance, err)
+}
+
+type scopedField interface {
+	inject(c *Container, name string)
+	valueType() reflect.Type
+}
+
+// Scoped is injected in place of a request-scoped component into singletons
+// such as controllers, which resolve it for the current request with Get.
+// The fall tag names the component or is empty to resolve it by type:
+//
+//	UnitOfWork fall.Scoped[*UnitOfWork] `fall:"unitOfWork"`
+type Scoped[T any] struct {
+	container *Container
+	name      string
+}
+
+func (s *Scoped[T]) inject(c *Container, name string) {
+	s.container, s.name = c, name
+}
+
+func (s *Scoped[T]) valueType() reflect.Type {
+	return reflect.TypeFor[T]()
+}
+
+func (s Scoped[T]) Get(ctx context.Context) Result[T] {
+	c := s.container
+	if c == nil {
+		c = defaultContainer
+	}
+	scope := scopeFrom(ctx)
+	if s.name == "" {
+		return getScoped[T](c, scope)
+	}
+	instance, err := c.resolveScoped(s.name, scope)
+	return namedResult[T](s.name, instance, err)
+}
@@ -0,0 +1,109 @@
+package fall
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type unitOfWork struct {
+	Repository *repository `fall:"repository"`
+	closed     bool
+}
+
+func (u *unitOfWork) Close() error {
+	u.closed = true
+	return nil
+}
+
+type unitOfWorkController struct {
+	UnitOfWork Scoped[*unitOfWork] `fall:"unitOfWork"`
+}
+
+func newScopeContainer(constructor func() (any, error)) *Container {
+	c := NewContainer()
+	c.Register("repository", func() (any, error) { return &repository{}, nil }, As[*repository]())
+	c.Register("unitOfWork", constructor, As[*unitOfWork](), WithLifetime(RequestScoped))
+	return c
+}
+
+func TestScopeInstances(t *testing.T) {
+	c := newScopeContainer(func() (any, error) { return &unitOfWork{}, nil })
+	first, second := c.NewScope(), c.NewScope()
+
+	a, err := c.resolveScoped("unitOfWork", first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := c.resolveScoped("unitOfWork", first)
+	other, _ := c.resolveScoped("unitOfWork", second)
+	if a != b {
+		t.Error("a scope must reuse its instance")
+	}
+	if a == other {
+		t.Error("scopes must not share instances")
+	}
+	repository := GetNamedFrom[*repository](c, "repository").Value
+	if a.(*unitOfWork).Repository != repository {
+		t.Error("request-scoped components must get the singleton")
+	}
+
+	if err := first.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !a.(*unitOfWork).closed {
+		t.Error("Close must close the components of the scope")
+	}
+	if _, err := c.Resolve("unitOfWork"); err == nil || !strings.Contains(err.Error(), "request scoped") {
+		t.Errorf("err = %v, want a request scoped error", err)
+	}
+}
+
+func TestScopedField(t *testing.T) {
+	c := newScopeContainer(func() (any, error) { return &unitOfWork{}, nil })
+	c.Register("controller", func() (any, error) { return &unitOfWorkController{}, nil }, As[*unitOfWorkController]())
+	controller := GetNamedFrom[*unitOfWorkController](c, "controller")
+	if controller.Error != nil {
+		t.Fatal(controller.Error)
+	}
+
+	var got *unitOfWork
+	handler := c.RequestScope(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = controller.Value.UnitOfWork.Get(r.Context()).Value
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if got == nil {
+		t.Fatal("Scoped.Get returned nil")
+	}
+	if !got.closed {
+		t.Error("RequestScope must close the scope when the request ends")
+	}
+}
+
+func TestScopesResolveConcurrently(t *testing.T) {
+	const delay = 100 * time.Millisecond
+	c := newScopeContainer(func() (any, error) {
+		time.Sleep(delay)
+		return &unitOfWork{}, nil
+	})
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.resolveScoped("unitOfWork", c.NewScope()); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if elapsed := time.Since(start); elapsed >= 2*delay {
+		t.Errorf("three scopes took %s, want them built in parallel", elapsed)
+	}
+}
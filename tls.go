@@ -0,0 +1,173 @@
+package fall
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const clientCertificateContextKey contextKey = "fall.clientCertificate"
+
+// certReloader serves the certificate through tls.Config.GetCertificate and
+// swaps it atomically when the files on disk change, e.g. after cert-manager rotates them.
+type certReloader struct {
+	certFile string
+	keyFile  string
+	cert     atomic.Pointer[tls.Certificate]
+	mu       sync.Mutex
+	modTime  time.Time
+	logger   *slog.Logger
+}
+
+func newCertReloader(certFile, keyFile string) (*certReloader, error) {
+	reloader := &certReloader{certFile: certFile, keyFile: keyFile}
+	if err := reloader.reload(); err != nil {
+		return nil, err
+	}
+	return reloader, nil
+}
+
+func (c *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return c.cert.Load(), nil
+}
+
+func (c *certReloader) reload() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	modTime, err := c.latestModTime()
+	if err != nil {
+		return err
+	}
+	if !modTime.After(c.modTime) {
+		return nil
+	}
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return fmt.Errorf("loading certificate %s: %w", c.certFile, err)
+	}
+	c.cert.Store(&cert)
+	c.modTime = modTime
+	return nil
+}
+
+func (c *certReloader) latestModTime() (time.Time, error) {
+	var latest time.Time
+	for _, file := range []string{c.certFile, c.keyFile} {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+// watch keeps the previous certificate when a reload fails, so a pair that is
+// still being written is picked up on the next tick.
+func (c *certReloader) watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			before := c.cert.Load()
+			if err := c.reload(); err != nil {
+				c.logger.Error("TLS certificate reload failed", "certFile", c.certFile, "error", err)
+			} else if c.cert.Load() != before {
+				c.logger.Info("TLS certificate reloaded", "certFile", c.certFile)
+			}
+		}
+	}
+}
+
+func (a *App) tlsConfig() (*tls.Config, *certReloader, error) {
+	reloader, err := newCertReloader(a.tlsCertFile, a.tlsKeyFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	reloader.logger = a.logger
+	config := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: reloader.GetCertificate,
+	}
+	if a.tlsClientCAFile != "" {
+		pem, err := os.ReadFile(a.tlsClientCAFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, nil, fmt.Errorf("no certificates found in %s", a.tlsClientCAFile)
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = a.tlsClientAuth
+	}
+	return config, reloader, nil
+}
+
+func (a *App) startHTTPRedirect() error {
+	redirectListener, err := net.Listen("tcp", a.httpRedirectAddr)
+	if err != nil {
+		return err
+	}
+	a.redirectServer = &http.Server{
+		ReadHeaderTimeout: a.server.ReadHeaderTimeout,
+		IdleTimeout:       a.server.IdleTimeout,
+		ErrorLog:          a.server.ErrorLog,
+		Handler:           redirectToHTTPS(a.redirectPort),
+	}
+	go func() {
+		a.logger.Info("HTTP redirect server started", "listenAddr", redirectListener.Addr().String())
+		if err := a.redirectServer.Serve(redirectListener); err != http.ErrServerClosed {
+			a.logger.Error("HTTP redirect server failed", "error", err)
+		}
+	}()
+	return nil
+}
+
+// redirectToHTTPS sends clients to the host they asked for on port, the
+// public HTTPS port, which is not the one the App listens on behind a proxy
+// or a Kubernetes Service.
+func redirectToHTTPS(port int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = r.Host
+		}
+		if port != 0 && port != 443 {
+			host = net.JoinHostPort(host, strconv.Itoa(port))
+		}
+		target := "https://" + host + r.URL.RequestURI()
+		http.Redirect(w, r, target, http.StatusPermanentRedirect)
+	})
+}
+
+// clientCertificate stores the verified mTLS client certificate in the request context.
+func clientCertificate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 && len(r.TLS.VerifiedChains[0]) > 0 {
+			ctx := context.WithValue(r.Context(), clientCertificateContextKey, r.TLS.VerifiedChains[0][0])
+			r = r.WithContext(ctx)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ClientCertificate returns the verified client certificate of an mTLS request.
+func ClientCertificate(r *http.Request) Result[*x509.Certificate] {
+	return GetContextValue[*x509.Certificate](r, clientCertificateContextKey)
+}
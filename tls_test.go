@@ -0,0 +1,33 @@
+package fall
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRedirectToHTTPS(t *testing.T) {
+	tests := []struct {
+		port   int
+		target string
+		want   string
+	}{
+		{target: "http://example.com/a?b=c", want: "https://example.com/a?b=c"},
+		{target: "http://example.com:8080/a", want: "https://example.com/a"},
+		{port: 443, target: "http://example.com:80/", want: "https://example.com/"},
+		{port: 8443, target: "http://example.com/a", want: "https://example.com:8443/a"},
+		{port: 8443, target: "http://[::1]:80/", want: "https://[::1]:8443/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			redirectToHTTPS(tt.port).ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			if w.Code != http.StatusPermanentRedirect {
+				t.Errorf("status = %d", w.Code)
+			}
+			if got := w.Header().Get("Location"); got != tt.want {
+				t.Errorf("Location = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
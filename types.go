@@ -1,5 +1,8 @@
 package fall
 
+import "context"
+
+// Environment selects framework defaults, see Development, Test, Staging and Production.
 type Environment string
 
 type Repository interface {
@@ -13,10 +16,27 @@ type Initializable interface {
 	Init() error
 }
 
+// Startable components are started by App.Run in dependency order before serving.
+type Startable interface {
+	Start(ctx context.Context) error
+}
+
+// Stoppable components are stopped by App.Shutdown in reverse dependency order.
+// Components implementing io.Closer instead are closed at the same point.
+type Stoppable interface {
+	Stop(ctx context.Context) error
+}
+
 type Controller interface {
 	Configure(r *Router)
 }
 
+// Ordered controllers are configured by ascending Order; the others have order 0
+// and keep their registration order.
+type Ordered interface {
+	Order() int
+}
+
 type UseCase[I any, O any] interface {
 	Execute(input I) (O, error)
 }
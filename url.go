@@ -0,0 +1,109 @@
+package fall
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// URL builds the path of the route registered under name. params are key/value
+// pairs: keys matching a wildcard of the pattern fill it, including {path...}
+// wildcards, and the remaining pairs become the query string.
+//
+//	router.URL("posts.show", "id", 42, "tab", "comments") // "/posts/42?tab=comments"
+func (r *Router) URL(name string, params ...any) (string, error) {
+	return r.registry.url(name, params...)
+}
+
+func (a *App) URL(name string, params ...any) (string, error) {
+	return a.router.URL(name, params...)
+}
+
+// URLFor builds a route URL from inside a handler, see Router.URL.
+func URLFor(r *http.Request, name string, params ...any) (string, error) {
+	registry, ok := r.Context().Value(registryContextKey).(*routeRegistry)
+	if !ok {
+		return "", fmt.Errorf("router not found in request context")
+	}
+	return registry.url(name, params...)
+}
+
+var errRouteNotFound = errors.New("route not found")
+
+func (rr *routeRegistry) url(name string, params ...any) (string, error) {
+	rr.mu.RLock()
+	info, ok := rr.names[name]
+	mounts := []*RouteInfo{}
+	for _, route := range rr.routes {
+		if route.mounted != nil {
+			mounts = append(mounts, route)
+		}
+	}
+	rr.mu.RUnlock()
+	if !ok {
+		for _, mount := range mounts {
+			path, err := mount.mounted.registry.url(name, params...)
+			if !errors.Is(err, errRouteNotFound) {
+				return strings.TrimSuffix(mount.Pattern, "/") + path, err
+			}
+		}
+		return "", fmt.Errorf("%w: %s", errRouteNotFound, name)
+	}
+	if len(params)%2 != 0 {
+		return "", fmt.Errorf("route %s: params must be key/value pairs", name)
+	}
+
+	values := make(map[string]string, len(params)/2)
+	var keys []string
+	for i := 0; i < len(params); i += 2 {
+		key, ok := params[i].(string)
+		if !ok {
+			return "", fmt.Errorf("route %s: param key %v is not a string", name, params[i])
+		}
+		if _, exists := values[key]; !exists {
+			keys = append(keys, key)
+		}
+		values[key] = fmt.Sprint(params[i+1])
+	}
+
+	segments := strings.Split(info.Pattern, "/")
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+			continue
+		}
+		wildcard := segment[1 : len(segment)-1]
+		if wildcard == "$" {
+			segments[i] = ""
+			continue
+		}
+		wildcard, remainder := strings.CutSuffix(wildcard, "...")
+		value, ok := values[wildcard]
+		if !ok {
+			return "", fmt.Errorf("route %s: missing param %s", name, wildcard)
+		}
+		delete(values, wildcard)
+		if remainder {
+			parts := strings.Split(strings.TrimPrefix(value, "/"), "/")
+			for j, part := range parts {
+				parts[j] = url.PathEscape(part)
+			}
+			segments[i] = strings.Join(parts, "/")
+		} else {
+			segments[i] = url.PathEscape(value)
+		}
+	}
+	path := strings.Join(segments, "/")
+
+	query := url.Values{}
+	for _, key := range keys {
+		if value, ok := values[key]; ok {
+			query.Add(key, value)
+		}
+	}
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	return path, nil
+}
@@ -0,0 +1,57 @@
+package fall
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func noopHandler(w http.ResponseWriter, r *http.Request) {}
+
+func TestRouterURL(t *testing.T) {
+	admin := NewRouter("")
+	admin.Get("/users/{id}", noopHandler).Name("admin.users.show")
+
+	r := NewRouter("")
+	r.Get("/{$}", noopHandler).Name("home")
+	r.Get("/users/{id:int}", noopHandler).Name("users.show")
+	r.Get("/files/{path...}", noopHandler).Name("files")
+	r.Group("/api", func(api *Router) {
+		api.Get("/posts/{post_id}/comments/{id}", noopHandler).Name("comments.show")
+	})
+	r.Mount("/admin", admin)
+
+	tests := []struct {
+		name    string
+		params  []any
+		want    string
+		wantErr bool
+	}{
+		{name: "home", want: "/"},
+		{name: "users.show", params: []any{"id", 42}, want: "/users/42"},
+		{name: "users.show", params: []any{"id", 1, "tab", "posts"}, want: "/users/1?tab=posts"},
+		{name: "users.show", params: []any{"id", "a b"}, want: "/users/a%20b"},
+		{name: "files", params: []any{"path", "docs/a b.txt"}, want: "/files/docs/a%20b.txt"},
+		{name: "comments.show", params: []any{"post_id", 1, "id", 2}, want: "/api/posts/1/comments/2"},
+		{name: "admin.users.show", params: []any{"id", 7}, want: "/admin/users/7"},
+		{name: "users.show", wantErr: true},
+		{name: "users.show", params: []any{"id"}, wantErr: true},
+		{name: "users.show", params: []any{1, 1}, wantErr: true},
+		{name: "missing", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.URL(tt.name, tt.params...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := r.URL("missing"); !errors.Is(err, errRouteNotFound) {
+		t.Errorf("err = %v, want errRouteNotFound", err)
+	}
+}
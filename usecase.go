@@ -0,0 +1,231 @@
+package fall
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type handleConfig struct {
+	status        int
+	errorStatuses []errorStatus
+}
+
+type errorStatus struct {
+	target error
+	status int
+}
+
+type HandleOption func(c *handleConfig)
+
+// SuccessStatus replaces the 200 answered when Execute succeeds, e.g. with 201.
+func SuccessStatus(status int) HandleOption {
+	return func(c *handleConfig) {
+		c.status = status
+	}
+}
+
+// ErrorStatus answers status when the error of Execute matches target with errors.Is.
+func ErrorStatus(target error, status int) HandleOption {
+	return func(c *handleConfig) {
+		c.errorStatuses = append(c.errorStatuses, errorStatus{target, status})
+	}
+}
+
+// StatusCoder errors choose the status of their own response.
+type StatusCoder interface {
+	StatusCode() int
+}
+
+type bindError struct {
+	err error
+}
+
+func (e *bindError) Error() string {
+	return e.err.Error()
+}
+
+func (e *bindError) Unwrap() error {
+	return e.err
+}
+
+func (e *bindError) StatusCode() int {
+	return http.StatusBadRequest
+}
+
+// Handle adapts uc to an http.HandlerFunc. The input is decoded from the JSON
+// body and then from the `path`, `query` and `header` tags of its fields, and
+// validated when it implements Validate() error. The output is encoded as JSON,
+// with 204 when it is nil or an empty slice. Errors are answered as ErrorsDTO
+// with the status of the first matching ErrorStatus option, of a StatusCoder
+// error, 400 for binding and validation errors, 404 for records not found
+// and 422 otherwise.
+func Handle[I any, O any](uc UseCase[I, O], opts ...HandleOption) http.HandlerFunc {
+	config := handleConfig{status: http.StatusOK}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		input, err := bindInput[I](r)
+		if err != nil {
+			replyHandleError(w, &config, err)
+			return
+		}
+
+		output, err := uc.Execute(input)
+		if err != nil {
+			replyHandleError(w, &config, err)
+			return
+		}
+
+		var result any = output
+		if isNil(result) || isSliceEmpty(result) {
+			if config.status == http.StatusOK {
+				w.WriteHeader(http.StatusNoContent)
+			} else {
+				w.WriteHeader(config.status)
+			}
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(config.status)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+// HandleUseCase registers Handle(uc, opts...) on r and records the input and
+// output types of uc in the route info.
+func HandleUseCase[I any, O any](r *Router, method, path string, uc UseCase[I, O], opts ...HandleOption) *Route {
+	route := r.handle(method, r.path(path), Handle(uc, opts...))
+	return route.each(func(info *RouteInfo) {
+		info.Handler = fmt.Sprintf("%T", uc)
+		info.Request = reflect.TypeFor[I]()
+		info.Response = reflect.TypeFor[O]()
+	})
+}
+
+func bindInput[I any](r *http.Request) (I, error) {
+	var input I
+	target := reflect.ValueOf(&input).Elem()
+	if target.Kind() == reflect.Ptr && target.Type().Elem().Kind() == reflect.Struct {
+		target.Set(reflect.New(target.Type().Elem()))
+		target = target.Elem()
+	}
+
+	if r.Body != nil && r.Body != http.NoBody && r.Method != http.MethodGet && r.Method != http.MethodHead {
+		err := json.NewDecoder(r.Body).Decode(target.Addr().Interface())
+		if err != nil && !errors.Is(err, io.EOF) {
+			return input, &bindError{fmt.Errorf("invalid body: %w", err)}
+		}
+	}
+
+	if target.Kind() == reflect.Struct {
+		if err := bindFields(r, target); err != nil {
+			return input, &bindError{err}
+		}
+	}
+
+	if validator, ok := target.Addr().Interface().(validator); ok {
+		if err := validator.Validate(); err != nil {
+			return input, &bindError{err}
+		}
+	}
+	return input, nil
+}
+
+func bindFields(r *http.Request, val reflect.Value) error {
+	var errs []error
+	typ := val.Type()
+	query := r.URL.Query()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		fieldVal := val.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if err := bindFields(r, fieldVal); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+
+		var source, name string
+		var values []string
+		if name = field.Tag.Get("path"); name != "" {
+			source = "path"
+			if value := r.PathValue(name); value != "" {
+				values = []string{value}
+			}
+		} else if name = field.Tag.Get("query"); name != "" {
+			source = "query"
+			values = query[name]
+		} else if name = field.Tag.Get("header"); name != "" {
+			source = "header"
+			values = r.Header.Values(name)
+		}
+		if len(values) == 0 {
+			continue
+		}
+
+		value := strings.Join(values, ",")
+		if err := decodeString(fieldVal, value, ",", nil); err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s value %s: %w", source, name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func replyHandleError(w http.ResponseWriter, config *handleConfig, err error) {
+	status := http.StatusUnprocessableEntity
+	var coder StatusCoder
+	switch {
+	case matchErrorStatus(config.errorStatuses, err, &status):
+	case errors.As(err, &coder):
+		status = coder.StatusCode()
+	case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, sql.ErrNoRows):
+		status = http.StatusNotFound
+	}
+
+	messages := []string{err.Error()}
+	if joined, ok := errors.Unwrap(err).(interface{ Unwrap() []error }); ok {
+		messages = messages[:0]
+		for _, e := range joined.Unwrap() {
+			messages = append(messages, e.Error())
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(ErrorsDTO{Errors: messages})
+}
+
+func matchErrorStatus(errorStatuses []errorStatus, err error, status *int) bool {
+	for _, errorStatus := range errorStatuses {
+		if errors.Is(err, errorStatus.target) {
+			*status = errorStatus.status
+			return true
+		}
+	}
+	return false
+}
+
+func isNil(value any) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
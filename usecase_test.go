@@ -0,0 +1,136 @@
+package fall
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+type updateUserInput struct {
+	ID      int      `path:"id"`
+	Name    string   `json:"name"`
+	Tags    []string `query:"tag"`
+	Tenant  string   `header:"X-Tenant"`
+	Failure string   `query:"fail"`
+}
+
+func (in updateUserInput) Validate() error {
+	if in.Name == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+type updateUserOutput struct {
+	ID     int      `json:"id"`
+	Name   string   `json:"name"`
+	Tags   []string `json:"tags"`
+	Tenant string   `json:"tenant"`
+}
+
+var errUserLocked = errors.New("user locked")
+
+type statusError struct{}
+
+func (statusError) Error() string   { return "conflict" }
+func (statusError) StatusCode() int { return http.StatusConflict }
+
+type updateUser struct{}
+
+func (updateUser) Execute(in updateUserInput) (*updateUserOutput, error) {
+	switch in.Failure {
+	case "locked":
+		return nil, fmt.Errorf("updating %d: %w", in.ID, errUserLocked)
+	case "coder":
+		return nil, statusError{}
+	case "missing":
+		return nil, gorm.ErrRecordNotFound
+	case "other":
+		return nil, errors.New("rejected")
+	case "empty":
+		return nil, nil
+	}
+	return &updateUserOutput{ID: in.ID, Name: in.Name, Tags: in.Tags, Tenant: in.Tenant}, nil
+}
+
+func TestHandleBinding(t *testing.T) {
+	r := NewRouter("")
+	r.Put("/users/{id}", Handle[updateUserInput, *updateUserOutput](updateUser{}))
+
+	req := httptest.NewRequest(http.MethodPut, "/users/7?tag=a&tag=b", strings.NewReader(`{"name":"Ana"}`))
+	req.Header.Set("X-Tenant", "acme")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", w.Code, w.Body)
+	}
+	var got updateUserOutput
+	if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	want := updateUserOutput{ID: 7, Name: "Ana", Tags: []string{"a", "b"}, Tenant: "acme"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("output = %+v, want %+v", got, want)
+	}
+}
+
+func TestHandleStatus(t *testing.T) {
+	r := NewRouter("")
+	r.Put("/users/{id}", Handle[updateUserInput, *updateUserOutput](updateUser{}, ErrorStatus(errUserLocked, http.StatusLocked)))
+	r.Post("/users/{id}", Handle[updateUserInput, *updateUserOutput](updateUser{}, SuccessStatus(http.StatusCreated)))
+
+	tests := []struct {
+		method, target, body string
+		status               int
+		errors               string
+	}{
+		{method: http.MethodPut, target: "/users/1", body: `{"name":"Ana"}`, status: http.StatusOK},
+		{method: http.MethodPost, target: "/users/1", body: `{"name":"Ana"}`, status: http.StatusCreated},
+		{method: http.MethodPut, target: "/users/1?fail=empty", body: `{"name":"Ana"}`, status: http.StatusNoContent},
+		{method: http.MethodPost, target: "/users/1?fail=empty", body: `{"name":"Ana"}`, status: http.StatusCreated},
+		{method: http.MethodPut, target: "/users/x", body: `{"name":"Ana"}`, status: http.StatusBadRequest},
+		{method: http.MethodPut, target: "/users/1", body: `{"name":`, status: http.StatusBadRequest},
+		{method: http.MethodPut, target: "/users/1", body: `{}`, status: http.StatusBadRequest, errors: "name is required"},
+		{method: http.MethodPut, target: "/users/1?fail=locked", body: `{"name":"Ana"}`, status: http.StatusLocked, errors: "updating 1: user locked"},
+		{method: http.MethodPut, target: "/users/1?fail=coder", body: `{"name":"Ana"}`, status: http.StatusConflict},
+		{method: http.MethodPut, target: "/users/1?fail=missing", body: `{"name":"Ana"}`, status: http.StatusNotFound},
+		{method: http.MethodPut, target: "/users/1?fail=other", body: `{"name":"Ana"}`, status: http.StatusUnprocessableEntity, errors: "rejected"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.target+" "+tt.body, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body)))
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d, body %s", w.Code, tt.status, w.Body)
+			}
+			if tt.errors == "" {
+				return
+			}
+			var dto ErrorsDTO
+			if err := json.NewDecoder(w.Body).Decode(&dto); err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(dto.Errors, "; ") != tt.errors {
+				t.Errorf("errors = %q, want %q", dto.Errors, tt.errors)
+			}
+		})
+	}
+}
+
+func TestHandleUseCaseRouteInfo(t *testing.T) {
+	r := NewRouter("")
+	HandleUseCase[updateUserInput, *updateUserOutput](r, http.MethodPut, "/users/{id}", updateUser{})
+
+	route := r.Routes()[0]
+	if route.Handler != "fall.updateUser" || route.Request != reflect.TypeFor[updateUserInput]() || route.Response != reflect.TypeFor[*updateUserOutput]() {
+		t.Errorf("route = %s %v %v", route.Handler, route.Request, route.Response)
+	}
+}
@@ -0,0 +1,255 @@
+package fall
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var acceptVersionRegex = regexp.MustCompile(`application/vnd\.[^;,\s]*?\.?(v\d+(?:\.\d+)*)\+`)
+
+type apiVersion struct {
+	name       string
+	number     []int
+	parent     *Router
+	prefix     string
+	deprecated time.Time
+	sunset     time.Time
+}
+
+type VersionOption func(v *apiVersion)
+
+// VersionDeprecated marks the version as deprecated since date and sends the Deprecation header.
+func VersionDeprecated(date time.Time) VersionOption {
+	return func(v *apiVersion) {
+		v.deprecated = date
+	}
+}
+
+// VersionSunset sends the Sunset header with the date the version stops being served.
+func VersionSunset(date time.Time) VersionOption {
+	return func(v *apiVersion) {
+		v.sunset = date
+	}
+}
+
+// versionedRoute dispatches an unversioned pattern to the route of the
+// requested version or to fallback, the route registered without a version.
+type versionedRoute struct {
+	mu         sync.RWMutex
+	versions   []*apiVersion
+	handlers   map[string]http.Handler
+	fallback   http.Handler
+	router     *Router
+	registered bool
+	info       *RouteInfo
+}
+
+// Version creates a group for routes of one API version, e.g. "v2". Every route is
+// served under the version prefix, "/api/v2/users", and also without it,
+// "/api/users", where the version comes from the API-Version header or an
+// Accept header like "application/vnd.example.v2+json". Without either, or
+// when the route does not exist in the requested version, the latest version
+// up to the requested one serves the request. A route registered on the
+// parent without a version, such as the API in place before versioning,
+// keeps serving requests that ask for no version or an older one.
+func (r *Router) Version(version string, fn func(r *Router), opts ...VersionOption) {
+	number, err := parseVersion(version)
+	if err != nil {
+		panic(err)
+	}
+	v := &apiVersion{
+		name:   version,
+		number: number,
+		parent: r,
+		prefix: r.path(version),
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	fn(&Router{
+		prefix:       v.prefix,
+		ServeMux:     r.ServeMux,
+		chain:        append([]Middleware{v.headers}, r.chain...),
+		registry:     r.registry,
+		host:         r.host,
+		resourceName: r.resourceName,
+		version:      v,
+	})
+}
+
+func parseVersion(version string) ([]int, error) {
+	parts := strings.Split(strings.TrimPrefix(strings.ToLower(version), "v"), ".")
+	number := make([]int, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid API version %q", version)
+		}
+		number[i] = n
+	}
+	return number, nil
+}
+
+func (v *apiVersion) headers(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("API-Version", v.name)
+		if !v.deprecated.IsZero() {
+			w.Header().Set("Deprecation", fmt.Sprintf("@%d", v.deprecated.Unix()))
+		}
+		if !v.sunset.IsZero() {
+			w.Header().Set("Sunset", v.sunset.UTC().Format(http.TimeFormat))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// dispatcher returns the versionedRoute of pattern in the mux of r, creating
+// it for router when missing.
+func (r *Router) dispatcher(pattern string, router *Router) *versionedRoute {
+	key := r.host.String() + " " + pattern
+	r.registry.mu.Lock()
+	defer r.registry.mu.Unlock()
+	if r.registry.versioned == nil {
+		r.registry.versioned = make(map[string]*versionedRoute)
+	}
+	route, ok := r.registry.versioned[key]
+	if !ok {
+		route = &versionedRoute{handlers: make(map[string]http.Handler), router: router}
+		r.registry.versioned[key] = route
+	}
+	return route
+}
+
+// registerRoute registers handler of a route outside of any version through
+// the dispatcher of its pattern, so versions of the same path added before or
+// after fall back to it.
+func (r *Router) registerRoute(pattern string, handler http.Handler, info *RouteInfo) bool {
+	route := r.dispatcher(pattern, r)
+	route.mu.Lock()
+	if route.fallback != nil {
+		route.mu.Unlock()
+		r.registry.addError(r.registry.conflict(info, "pattern already registered"))
+		return false
+	}
+	route.fallback, route.router = routeHandler{Handler: handler, info: info}, r
+	listed, registered := route.info, route.registered
+	route.info = nil
+	route.mu.Unlock()
+
+	if listed != nil {
+		r.registry.remove(listed)
+	}
+	if registered {
+		return true
+	}
+	if !r.register(pattern, route, info) {
+		route.mu.Lock()
+		route.fallback = nil
+		route.mu.Unlock()
+		return false
+	}
+	route.mu.Lock()
+	route.registered = true
+	route.mu.Unlock()
+	return true
+}
+
+// registerVersioned adds handler of a version route to the dispatcher of its
+// unversioned pattern, registering the dispatcher on the first version. Until
+// a route without a version takes the pattern, the dispatcher is listed by
+// Routes without a version.
+func (r *Router) registerVersioned(info *RouteInfo, path string, handler http.Handler) {
+	parent := r.version.parent
+	unversioned := parent.format(parent.prefix) + strings.TrimPrefix(path, r.version.prefix)
+	if unversioned == "" {
+		unversioned = "/"
+	}
+	pattern := strings.TrimSpace(info.Method + " " + unversioned)
+
+	route := r.dispatcher(pattern, parent)
+	route.mu.Lock()
+	route.versions = append(route.versions, r.version)
+	slices.SortFunc(route.versions, func(a, b *apiVersion) int { return slices.Compare(a.number, b.number) })
+	route.handlers[r.version.name] = routeHandler{Handler: handler, info: info}
+	first := len(route.versions) == 1 && route.fallback == nil
+	route.mu.Unlock()
+	// Só a primeira versão registra o dispatcher; um conflito já está em Err
+	if !first {
+		return
+	}
+
+	dispatcher := &RouteInfo{
+		Host:        r.host.String(),
+		Method:      info.Method,
+		Pattern:     unversioned,
+		Handler:     info.Handler,
+		Controller:  r.registry.controller,
+		Middlewares: info.Middlewares,
+	}
+	if !r.register(pattern, route, dispatcher) {
+		return
+	}
+	route.mu.Lock()
+	route.registered, route.info = true, dispatcher
+	route.mu.Unlock()
+	r.registry.add(dispatcher)
+}
+
+func (vr *versionedRoute) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if handler := vr.choose(requestedVersion(req)); handler != nil {
+		handler.ServeHTTP(w, req)
+		return
+	}
+	vr.mu.RLock()
+	router := vr.router
+	vr.mu.RUnlock()
+	fallback := router.registry.fallbackFor(router, req.URL.Path)
+	fallback.serveFallback(w, req, fallback.notFoundHandler())
+}
+
+// routeFor returns the route of the version serving req.
+func (vr *versionedRoute) routeFor(req *http.Request) *RouteInfo {
+	route, _ := vr.choose(requestedVersion(req)).(routeHandler)
+	return route.info
+}
+
+func requestedVersion(r *http.Request) []int {
+	version := r.Header.Get("API-Version")
+	if version == "" {
+		if match := acceptVersionRegex.FindStringSubmatch(r.Header.Get("Accept")); match != nil {
+			version = match[1]
+		}
+	}
+	if version == "" {
+		return nil
+	}
+	number, err := parseVersion(version)
+	if err != nil {
+		return nil
+	}
+	return number
+}
+
+// choose returns the handler of the latest version not newer than requested,
+// or of the latest version when requested is nil. The route without a version
+// is chosen when no version is requested or none matches.
+func (vr *versionedRoute) choose(requested []int) http.Handler {
+	vr.mu.RLock()
+	defer vr.mu.RUnlock()
+	if requested == nil && vr.fallback != nil {
+		return vr.fallback
+	}
+	for i := len(vr.versions) - 1; i >= 0; i-- {
+		version := vr.versions[i]
+		if requested == nil || slices.Compare(version.number, requested) <= 0 {
+			return vr.handlers[version.name]
+		}
+	}
+	return vr.fallback
+}
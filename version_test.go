@@ -0,0 +1,169 @@
+package fall
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+func versionHandler(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}
+}
+
+func TestRequestedVersion(t *testing.T) {
+	tests := []struct {
+		header string
+		accept string
+		want   []int
+	}{
+		{},
+		{header: "2", want: []int{2}},
+		{header: "v1.1", want: []int{1, 1}},
+		{header: "latest"},
+		{accept: "application/vnd.example.v2+json", want: []int{2}},
+		{accept: "application/vnd.example.com.v3.1+json; charset=utf-8", want: []int{3, 1}},
+		{accept: "text/html, application/vnd.v4+json", want: []int{4}},
+		{accept: "application/json"},
+		{header: "1", accept: "application/vnd.example.v2+json", want: []int{1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header+"|"+tt.accept, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set("API-Version", tt.header)
+			}
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			if got := requestedVersion(req); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersionDispatch(t *testing.T) {
+	deprecated := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+	r := NewRouter("")
+	r.Group("/api", func(api *Router) {
+		api.Version("v1", func(v1 *Router) {
+			v1.Get("/users", versionHandler("v1 users"))
+			v1.Get("/legacy", versionHandler("v1 legacy"))
+		}, VersionDeprecated(deprecated), VersionSunset(sunset))
+		api.Version("v2", func(v2 *Router) {
+			v2.Get("/users", versionHandler("v2 users"))
+		})
+	})
+	if err := r.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path       string
+		version    string
+		status     int
+		body       string
+		deprecated bool
+	}{
+		{path: "/api/v1/users", status: http.StatusOK, body: "v1 users", deprecated: true},
+		{path: "/api/v2/users", status: http.StatusOK, body: "v2 users"},
+		{path: "/api/users", status: http.StatusOK, body: "v2 users"},
+		{path: "/api/users", version: "1", status: http.StatusOK, body: "v1 users", deprecated: true},
+		{path: "/api/users", version: "3", status: http.StatusOK, body: "v2 users"},
+		{path: "/api/legacy", version: "2", status: http.StatusOK, body: "v1 legacy", deprecated: true},
+		{path: "/api/users", version: "0", status: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path+"@"+tt.version, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.version != "" {
+				req.Header.Set("API-Version", tt.version)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+			if tt.body != "" && w.Body.String() != tt.body {
+				t.Errorf("body = %q, want %q", w.Body.String(), tt.body)
+			}
+			if got := w.Header().Get("Deprecation") != ""; got != tt.deprecated {
+				t.Errorf("Deprecation = %q, want it set: %v", w.Header().Get("Deprecation"), tt.deprecated)
+			}
+			if tt.deprecated {
+				if got := w.Header().Get("Deprecation"); got != "@1767225600" {
+					t.Errorf("Deprecation = %q", got)
+				}
+				if got := w.Header().Get("Sunset"); got != "Thu, 31 Dec 2026 00:00:00 GMT" {
+					t.Errorf("Sunset = %q", got)
+				}
+			}
+		})
+	}
+}
+
+func TestVersionFallsBackToUnversionedRoute(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(api *Router)
+	}{
+		{
+			name: "route first",
+			setup: func(api *Router) {
+				api.Get("/users", versionHandler("current users"))
+				api.Version("v2", func(v2 *Router) { v2.Get("/users", versionHandler("v2 users")) })
+			},
+		},
+		{
+			name: "version first",
+			setup: func(api *Router) {
+				api.Version("v2", func(v2 *Router) { v2.Get("/users", versionHandler("v2 users")) })
+				api.Get("/users", versionHandler("current users"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRouter("")
+			r.Group("/api", tt.setup)
+			if err := r.Err(); err != nil {
+				t.Fatal(err)
+			}
+
+			for version, want := range map[string]string{"": "current users", "1": "current users", "2": "v2 users"} {
+				req := httptest.NewRequest(http.MethodGet, "/api/users", nil)
+				if version != "" {
+					req.Header.Set("API-Version", version)
+				}
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, req)
+				if w.Body.String() != want {
+					t.Errorf("version %q: body = %q, want %q", version, w.Body.String(), want)
+				}
+			}
+
+			var patterns []string
+			for _, route := range r.Routes() {
+				patterns = append(patterns, route.Pattern+"@"+route.Version)
+			}
+			slices.Sort(patterns)
+			if want := []string{"/api/users@", "/api/v2/users@v2"}; !slices.Equal(patterns, want) {
+				t.Errorf("routes = %v, want %v", patterns, want)
+			}
+		})
+	}
+}
+
+func TestVersionDuplicateRoute(t *testing.T) {
+	r := NewRouter("")
+	r.Get("/users", versionHandler("a"))
+	r.Get("/users", versionHandler("b"))
+	if err := r.Err(); err == nil {
+		t.Fatal("want a duplicate route error")
+	}
+}
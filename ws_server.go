@@ -3,6 +3,7 @@ package fall
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/gorilla/websocket"
 )
@@ -22,14 +23,14 @@ func (s *WebSocketServer[T]) AddConnection(clientID T, conn *websocket.Conn) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.connections[clientID] = conn
-	fmt.Printf("Client %s connected\n", clientID)
+	fmt.Printf("Client %v connected\n", clientID)
 }
 
 func (s *WebSocketServer[T]) RemoveConnection(clientID T) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	if _, ok := s.connections[clientID]; ok {
-		fmt.Printf("Client %s disconnected\n", clientID)
+		fmt.Printf("Client %v disconnected\n", clientID)
 		delete(s.connections, clientID)
 	}
 }
@@ -40,12 +41,25 @@ func (s *WebSocketServer[T]) SendMessage(clientID T, message []byte) {
 
 	conn, ok := s.connections[clientID]
 	if !ok {
-		fmt.Printf("Client %s not found\n", clientID)
+		fmt.Printf("Client %v not found\n", clientID)
 		return
 	}
 
 	if err := conn.WriteMessage(websocket.TextMessage, message); err != nil {
-		fmt.Printf("Error sending message to client %s: %v\n", clientID, err)
+		fmt.Printf("Error sending message to client %v: %v\n", clientID, err)
+		delete(s.connections, clientID)
+	}
+}
+
+func (s *WebSocketServer[T]) CloseConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deadline := time.Now().Add(time.Second)
+	message := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+	for clientID, conn := range s.connections {
+		conn.WriteControl(websocket.CloseMessage, message, deadline)
+		conn.Close()
 		delete(s.connections, clientID)
 	}
 }